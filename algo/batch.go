@@ -0,0 +1,153 @@
+package algo
+
+import "math"
+
+// The batch kernels compare one query against every row of a matrix, stored contiguously in row major order
+// (row i is matrix[i*len(query) : (i+1)*len(query)]). Compared to calling Cosine for each row, the query norm is
+// computed once, rows are read sequentially and loops are unrolled over independent accumulators, which the
+// compiler and CPU can pipeline (and vectorize where supported).
+//
+// ErrLengthMismatch is returned when the matrix is not made of whole rows or out does not have one slot per row,
+// and ErrZeroVector when the query has a zero magnitude. Rows with a zero magnitude score NaN.
+
+// checkBatch validates the shapes and returns the number of rows
+func checkBatch(dim int, matrixLen int, outLen int) (int, error) {
+	if dim == 0 || matrixLen%dim != 0 || matrixLen/dim != outLen {
+		return 0, ErrLengthMismatch
+	}
+	return outLen, nil
+}
+
+// CosineBatchUint32 writes in out the cosine similarity of query with each row of matrix
+func CosineBatchUint32(query []uint32, matrix []uint32, out []float64) error {
+	dim := len(query)
+	rows, err := checkBatch(dim, len(matrix), len(out))
+	if err != nil {
+		return err
+	}
+
+	queryNorm := Norm(query)
+	if queryNorm == 0 {
+		return ErrZeroVector
+	}
+
+	// converting the query once halves the conversions of the inner loop
+	q := make([]float64, dim)
+	for i, v := range query {
+		q[i] = float64(v)
+	}
+
+	for r := range rows {
+		row := matrix[r*dim : (r+1)*dim : (r+1)*dim]
+
+		var dot0, dot1, dot2, dot3 float64
+		var sq0, sq1, sq2, sq3 float64
+		i := 0
+		for ; i+4 <= dim; i += 4 {
+			qs := q[i : i+4 : i+4] // lets the compiler drop the bounds checks
+			vs := row[i : i+4 : i+4]
+			v0, v1, v2, v3 := float64(vs[0]), float64(vs[1]), float64(vs[2]), float64(vs[3])
+			dot0 += qs[0] * v0
+			dot1 += qs[1] * v1
+			dot2 += qs[2] * v2
+			dot3 += qs[3] * v3
+			sq0 += v0 * v0
+			sq1 += v1 * v1
+			sq2 += v2 * v2
+			sq3 += v3 * v3
+		}
+		for ; i < dim; i++ {
+			v := float64(row[i])
+			dot0 += q[i] * v
+			sq0 += v * v
+		}
+
+		rowNorm := math.Sqrt(sq0 + sq1 + sq2 + sq3)
+		if rowNorm == 0 {
+			out[r] = math.NaN()
+			continue
+		}
+		out[r] = (dot0 + dot1 + dot2 + dot3) / (queryNorm * rowNorm)
+	}
+
+	return nil
+}
+
+// CosineBatchFloat32 writes in out the cosine similarity of query with each row of matrix, computed in float32
+func CosineBatchFloat32(query []float32, matrix []float32, out []float32) error {
+	dim := len(query)
+	rows, err := checkBatch(dim, len(matrix), len(out))
+	if err != nil {
+		return err
+	}
+
+	queryNorm := float32(math.Sqrt(float64(dotFloat32(query, query))))
+	if queryNorm == 0 {
+		return ErrZeroVector
+	}
+
+	for r := range rows {
+		row := matrix[r*dim : (r+1)*dim : (r+1)*dim]
+
+		var dot0, dot1, dot2, dot3 float32
+		var sq0, sq1, sq2, sq3 float32
+		i := 0
+		for ; i+4 <= dim; i += 4 {
+			v0, v1, v2, v3 := row[i], row[i+1], row[i+2], row[i+3]
+			dot0 += query[i] * v0
+			dot1 += query[i+1] * v1
+			dot2 += query[i+2] * v2
+			dot3 += query[i+3] * v3
+			sq0 += v0 * v0
+			sq1 += v1 * v1
+			sq2 += v2 * v2
+			sq3 += v3 * v3
+		}
+		for ; i < dim; i++ {
+			dot0 += query[i] * row[i]
+			sq0 += row[i] * row[i]
+		}
+
+		rowNorm := float32(math.Sqrt(float64(sq0 + sq1 + sq2 + sq3)))
+		if rowNorm == 0 {
+			out[r] = float32(math.NaN())
+			continue
+		}
+		out[r] = (dot0 + dot1 + dot2 + dot3) / (queryNorm * rowNorm)
+	}
+
+	return nil
+}
+
+// DotBatchFloat32 writes in out the dot product of query with each row of matrix. A zero query is allowed
+func DotBatchFloat32(query []float32, matrix []float32, out []float32) error {
+	dim := len(query)
+	rows, err := checkBatch(dim, len(matrix), len(out))
+	if err != nil {
+		return err
+	}
+
+	for r := range rows {
+		out[r] = dotFloat32(query, matrix[r*dim:(r+1)*dim:(r+1)*dim])
+	}
+	return nil
+}
+
+// dotFloat32 is the kernel of the float32 batches, returning the dot product of a and b.
+// b must be at least as long as a, its extra values are ignored
+func dotFloat32(a []float32, b []float32) float32 {
+	b = b[:len(a)]
+
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		s0 += a[i] * b[i]
+		s1 += a[i+1] * b[i+1]
+		s2 += a[i+2] * b[i+2]
+		s3 += a[i+3] * b[i+3]
+	}
+	for ; i < len(a); i++ {
+		s0 += a[i] * b[i]
+	}
+	return s0 + s1 + s2 + s3
+}
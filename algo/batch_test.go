@@ -0,0 +1,153 @@
+package algo
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCosineBatchUint32(t *testing.T) {
+	query := []uint32{1, 2, 3, 4, 5}
+	matrix := []uint32{
+		1, 2, 3, 4, 5,
+		5, 4, 3, 2, 1,
+		0, 0, 0, 0, 0,
+		9, 0, 0, 0, 1,
+	}
+	out := make([]float64, 4)
+	assert.NoError(t, CosineBatchUint32(query, matrix, out))
+
+	for r := range 4 {
+		expected, err := Cosine(query, matrix[r*5:(r+1)*5])
+		if err != nil {
+			assert.True(t, math.IsNaN(out[r]))
+			continue
+		}
+		assert.InDelta(t, expected, out[r], 1e-9)
+	}
+
+	assert.ErrorIs(t, CosineBatchUint32(query, matrix[:7], out), ErrLengthMismatch)
+	assert.ErrorIs(t, CosineBatchUint32(query, matrix, out[:3]), ErrLengthMismatch)
+	assert.ErrorIs(t, CosineBatchUint32([]uint32{}, matrix, out), ErrLengthMismatch)
+	assert.ErrorIs(t, CosineBatchUint32(make([]uint32, 5), matrix, out), ErrZeroVector)
+}
+
+func TestCosineBatchFloat32(t *testing.T) {
+	query := []float32{0.5, -1, 2}
+	matrix := []float32{
+		0.5, -1, 2,
+		-0.5, 1, -2,
+		1, 0, 0,
+		0, 0, 0,
+	}
+	out := make([]float32, 4)
+	assert.NoError(t, CosineBatchFloat32(query, matrix, out))
+	assert.InDelta(t, 1, out[0], 1e-6)
+	assert.InDelta(t, -1, out[1], 1e-6)
+	expected, _ := Cosine(query, matrix[6:9])
+	assert.InDelta(t, expected, out[2], 1e-6)
+	assert.True(t, math.IsNaN(float64(out[3])))
+
+	assert.ErrorIs(t, CosineBatchFloat32(query, matrix[:5], out), ErrLengthMismatch)
+	assert.ErrorIs(t, CosineBatchFloat32([]float32{0, 0, 0}, matrix, out), ErrZeroVector)
+}
+
+func TestDotBatchFloat32(t *testing.T) {
+	query := []float32{1, 2, 3, 4, 5, 6}
+	matrix := []float32{
+		1, 1, 1, 1, 1, 1,
+		0, 0, 0, 0, 0, -1,
+	}
+	out := make([]float32, 2)
+	assert.NoError(t, DotBatchFloat32(query, matrix, out))
+	assert.Equal(t, []float32{21, -6}, out)
+	assert.Equal(t, float32(91), dotFloat32(query, query))
+}
+
+// The benchmarks compare the per pair functions with the batch kernels on a typical LSH re-ranking:
+// one query signature against 5000 candidates of 128 values
+
+const (
+	benchRows = 5000
+	benchDim  = 128
+)
+
+func givenBenchUint32() ([]uint32, [][]uint32, []uint32) {
+	query := make([]uint32, benchDim)
+	for i := range query {
+		query[i] = rand.Uint32()
+	}
+	rows := make([][]uint32, benchRows)
+	matrix := make([]uint32, 0, benchRows*benchDim)
+	for r := range rows {
+		rows[r] = make([]uint32, benchDim)
+		for i := range rows[r] {
+			rows[r][i] = rand.Uint32()
+		}
+		matrix = append(matrix, rows[r]...)
+	}
+	return query, rows, matrix
+}
+
+func givenBenchFloat32() ([]float32, [][]float32, []float32) {
+	query := make([]float32, benchDim)
+	for i := range query {
+		query[i] = rand.Float32()
+	}
+	rows := make([][]float32, benchRows)
+	matrix := make([]float32, 0, benchRows*benchDim)
+	for r := range rows {
+		rows[r] = make([]float32, benchDim)
+		for i := range rows[r] {
+			rows[r][i] = rand.Float32()
+		}
+		matrix = append(matrix, rows[r]...)
+	}
+	return query, rows, matrix
+}
+
+func BenchmarkCosineSimilarityUint32(b *testing.B) {
+	query, rows, _ := givenBenchUint32()
+	out := make([]float64, benchRows)
+	b.SetBytes(benchRows * benchDim * 4)
+	b.ResetTimer()
+	for range b.N {
+		for r := range rows {
+			out[r] = CosineSimilarityUint32(query, rows[r])
+		}
+	}
+}
+
+func BenchmarkCosineBatchUint32(b *testing.B) {
+	query, _, matrix := givenBenchUint32()
+	out := make([]float64, benchRows)
+	b.SetBytes(benchRows * benchDim * 4)
+	b.ResetTimer()
+	for range b.N {
+		_ = CosineBatchUint32(query, matrix, out)
+	}
+}
+
+func BenchmarkCosineFloat32(b *testing.B) {
+	query, rows, _ := givenBenchFloat32()
+	out := make([]float64, benchRows)
+	b.SetBytes(benchRows * benchDim * 4)
+	b.ResetTimer()
+	for range b.N {
+		for r := range rows {
+			out[r], _ = Cosine(query, rows[r])
+		}
+	}
+}
+
+func BenchmarkCosineBatchFloat32(b *testing.B) {
+	query, _, matrix := givenBenchFloat32()
+	out := make([]float32, benchRows)
+	b.SetBytes(benchRows * benchDim * 4)
+	b.ResetTimer()
+	for range b.N {
+		_ = CosineBatchFloat32(query, matrix, out)
+	}
+}
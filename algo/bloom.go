@@ -0,0 +1,91 @@
+package algo
+
+import "math"
+
+// BloomFilter tells whether a value was probably added, or definitely not. False positives happen at a rate depending
+// on its size and number of hash functions (see NewBloomFilterWithEstimates), false negatives never do.
+// It is not safe for concurrent use
+type BloomFilter struct {
+	m    uint64 // number of bits
+	k    uint64 // number of hash functions
+	bits []uint64
+}
+
+// NewBloomFilter returns an empty filter of m bits, setting k bits per value
+func NewBloomFilter(m uint64, k int) *BloomFilter {
+	if m < 1 || k < 1 {
+		panic("algo: bloom filter size and number of hash functions must be at least 1")
+	}
+	return &BloomFilter{
+		m:    m,
+		k:    uint64(k),
+		bits: make([]uint64, (m+63)/64),
+	}
+}
+
+// NewBloomFilterWithEstimates returns a filter sized for n values with a false positive rate of falsePositiveRate.
+// n must be at least 1 and falsePositiveRate between 0 and 1, both excluded
+func NewBloomFilterWithEstimates(n uint64, falsePositiveRate float64) *BloomFilter {
+	if n < 1 {
+		panic("algo: bloom filter estimated number of values must be at least 1")
+	}
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		panic("algo: bloom filter false positive rate must be between 0 and 1, both excluded")
+	}
+	m := math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	if m >= math.MaxUint64 {
+		panic("algo: bloom filter estimated size overflows, reduce n or raise falsePositiveRate")
+	}
+	k := math.Round(m / float64(n) * math.Ln2)
+	return NewBloomFilter(max(uint64(m), 1), max(int(k), 1))
+}
+
+// Add adds data to the filter
+func (f *BloomFilter) Add(data []byte) {
+	f.AddHash(Hash64(data, 0))
+}
+
+// AddString adds s to the filter
+func (f *BloomFilter) AddString(s string) {
+	f.AddHash(HashString64(s, 0))
+}
+
+// AddHash adds an already hashed value (see Hash64)
+func (f *BloomFilter) AddHash(h uint64) {
+	for i := range f.k {
+		pos := nthHash(h, i) % f.m
+		f.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// Test returns false when data was definitely not added, true when it probably was
+func (f *BloomFilter) Test(data []byte) bool {
+	return f.TestHash(Hash64(data, 0))
+}
+
+// TestString returns false when s was definitely not added, true when it probably was
+func (f *BloomFilter) TestString(s string) bool {
+	return f.TestHash(HashString64(s, 0))
+}
+
+// TestHash returns false when the hashed value was definitely not added, true when it probably was
+func (f *BloomFilter) TestHash(h uint64) bool {
+	for i := range f.k {
+		pos := nthHash(h, i) % f.m
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge adds the values of other to the filter. ErrIncompatibleSketch is returned when the sizes or number of hash functions differ
+func (f *BloomFilter) Merge(other *BloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrIncompatibleSketch
+	}
+	for i, b := range other.bits {
+		f.bits[i] |= b
+	}
+	return nil
+}
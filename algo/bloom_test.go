@@ -0,0 +1,49 @@
+package algo
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomFilter(t *testing.T) {
+	n := 10000
+	f := NewBloomFilterWithEstimates(uint64(n), 0.01)
+	for i := range n {
+		f.AddString("in " + strconv.Itoa(i))
+	}
+
+	for i := range n {
+		assert.True(t, f.TestString("in "+strconv.Itoa(i)))
+	}
+
+	falsePositives := 0
+	for i := range n {
+		if f.Test([]byte("out " + strconv.Itoa(i))) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, float64(falsePositives)/float64(n), 0.02)
+
+	t.Run("merge", func(t *testing.T) {
+		a := NewBloomFilter(1024, 3)
+		b := NewBloomFilter(1024, 3)
+		a.AddString("a")
+		b.Add([]byte("b"))
+		assert.NoError(t, a.Merge(b))
+		assert.True(t, a.TestString("a"))
+		assert.True(t, a.TestString("b"))
+
+		assert.ErrorIs(t, a.Merge(NewBloomFilter(1024, 4)), ErrIncompatibleSketch)
+	})
+
+	t.Run("estimates panic on invalid parameters", func(t *testing.T) {
+		assert.Panics(t, func() { NewBloomFilterWithEstimates(0, 0.01) })
+		for _, rate := range []float64{0, -0.1, 1, 1.5, math.NaN()} {
+			assert.Panics(t, func() { NewBloomFilterWithEstimates(100, rate) }, rate)
+		}
+		assert.Panics(t, func() { NewBloomFilterWithEstimates(math.MaxUint64, 1e-300) })
+	})
+}
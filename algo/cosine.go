@@ -1,63 +1,58 @@
 package algo
 
-import "math"
-
-func CosineSimilarityUint32(a []uint32, b []uint32) float64 {
+import (
+	"errors"
+	"math"
+)
+
+// Cosine returns the cosine similarity of a and b, from -1 (opposite) to 1 (same direction).
+// ErrLengthMismatch is returned when a and b don't have the same length, and ErrZeroVector when either of them has
+// a zero magnitude (including empty vectors), since the similarity is undefined
+func Cosine[T Number](a []T, b []T) (float64, error) {
 	if len(a) != len(b) {
-		panic("a and b must be of equal length")
+		return 0, ErrLengthMismatch
 	}
 
-	dotProduct := uint64(0)
-	sumA := uint64(0)
-	sumB := uint64(0)
+	dotProduct := 0.0
+	sumA := 0.0
+	sumB := 0.0
 	for i := range a {
-		dotProduct += uint64(a[i]) * uint64(b[i])
-		sumA += (uint64(a[i]) * uint64(a[i]))
-		sumB += (uint64(b[i]) * uint64(b[i]))
+		fa, fb := float64(a[i]), float64(b[i])
+		dotProduct += fa * fb
+		sumA += fa * fa
+		sumB += fb * fb
 	}
 
-	magnitudeA := math.Sqrt(float64(sumA))
-	magnitudeB := math.Sqrt(float64(sumB))
-
-	return float64(dotProduct) / (magnitudeA * magnitudeB)
-}
-
-func CosineSimilarityUint16(a []uint16, b []uint16) float64 {
-	if len(a) != len(b) {
-		panic("a and b must be of equal length")
+	if sumA == 0 || sumB == 0 {
+		return 0, ErrZeroVector
 	}
 
-	dotProduct := uint64(0)
-	sumA := uint64(0)
-	sumB := uint64(0)
-	for i := range a {
-		dotProduct += uint64(a[i]) * uint64(b[i])
-		sumA += (uint64(a[i]) * uint64(a[i]))
-		sumB += (uint64(b[i]) * uint64(b[i]))
-	}
+	return dotProduct / (math.Sqrt(sumA) * math.Sqrt(sumB)), nil
+}
 
-	magnitudeA := math.Sqrt(float64(sumA))
-	magnitudeB := math.Sqrt(float64(sumB))
+// Deprecated: use Cosine, which returns an error instead of panicking
+func CosineSimilarityUint32(a []uint32, b []uint32) float64 {
+	return mustCosine(a, b)
+}
 
-	return float64(dotProduct) / (magnitudeA * magnitudeB)
+// Deprecated: use Cosine, which returns an error instead of panicking
+func CosineSimilarityUint16(a []uint16, b []uint16) float64 {
+	return mustCosine(a, b)
 }
 
+// Deprecated: use Cosine, which returns an error instead of panicking
 func CosineSimilarity(a []int, b []int) float64 {
-	if len(a) != len(b) {
+	return mustCosine(a, b)
+}
+
+// mustCosine keeps the behaviour of the original cosine functions: panic on length mismatch and NaN on zero vectors
+func mustCosine[T Number](a []T, b []T) float64 {
+	similarity, err := Cosine(a, b)
+	if errors.Is(err, ErrLengthMismatch) {
 		panic("a and b must be of equal length")
 	}
-
-	dotProduct := 0
-	sumA := uint64(0)
-	sumB := uint64(0)
-	for i := range a {
-		dotProduct += a[i] * b[i]
-		sumA += (uint64(a[i]) * uint64(a[i]))
-		sumB += (uint64(b[i]) * uint64(b[i]))
+	if err != nil {
+		return math.NaN()
 	}
-
-	magnitudeA := math.Sqrt(float64(sumA))
-	magnitudeB := math.Sqrt(float64(sumB))
-
-	return float64(dotProduct) / (magnitudeA * magnitudeB)
+	return similarity
 }
@@ -0,0 +1,151 @@
+package algo
+
+import (
+	"math"
+	"sort"
+)
+
+// CountMinSketch estimates the frequency of the values of a stream in a fixed amount of memory.
+// Estimates are never under the real count, and over it by at most epsilon * Total() with a probability of 1 - delta
+// (see NewCountMinSketchWithEstimates). It is not safe for concurrent use
+type CountMinSketch struct {
+	width  uint64
+	depth  uint64
+	counts []uint64 // depth rows of width counters
+	total  uint64
+}
+
+// NewCountMinSketch returns an empty sketch of depth rows of width counters
+func NewCountMinSketch(width int, depth int) *CountMinSketch {
+	if width < 1 || depth < 1 {
+		panic("algo: count-min sketch width and depth must be at least 1")
+	}
+	return &CountMinSketch{
+		width:  uint64(width),
+		depth:  uint64(depth),
+		counts: make([]uint64, width*depth),
+	}
+}
+
+// NewCountMinSketchWithEstimates returns a sketch sized so that estimates exceed the real count by at most
+// epsilon * Total(), with a probability of 1 - delta. epsilon must be positive and delta between 0 and 1, both excluded
+func NewCountMinSketchWithEstimates(epsilon float64, delta float64) *CountMinSketch {
+	if !(epsilon > 0) || math.IsInf(epsilon, 1) {
+		panic("algo: count-min sketch epsilon must be positive")
+	}
+	if !(delta > 0 && delta < 1) {
+		panic("algo: count-min sketch delta must be between 0 and 1, both excluded")
+	}
+	width := math.Ceil(math.E / epsilon)
+	if width > math.MaxInt32 {
+		panic("algo: count-min sketch epsilon is too small")
+	}
+	depth := math.Ceil(math.Log(1 / delta))
+	return NewCountMinSketch(int(width), max(int(depth), 1))
+}
+
+// Add adds count occurrences of data
+func (s *CountMinSketch) Add(data []byte, count uint64) {
+	s.AddHash(Hash64(data, 0), count)
+}
+
+// AddString adds count occurrences of v
+func (s *CountMinSketch) AddString(v string, count uint64) {
+	s.AddHash(HashString64(v, 0), count)
+}
+
+// AddHash adds count occurrences of an already hashed value (see Hash64)
+func (s *CountMinSketch) AddHash(h uint64, count uint64) {
+	for row := range s.depth {
+		s.counts[row*s.width+nthHash(h, row)%s.width] += count
+	}
+	s.total += count
+}
+
+// Estimate returns the estimated number of occurrences of data
+func (s *CountMinSketch) Estimate(data []byte) uint64 {
+	return s.EstimateHash(Hash64(data, 0))
+}
+
+// EstimateString returns the estimated number of occurrences of v
+func (s *CountMinSketch) EstimateString(v string) uint64 {
+	return s.EstimateHash(HashString64(v, 0))
+}
+
+// EstimateHash returns the estimated number of occurrences of an already hashed value
+func (s *CountMinSketch) EstimateHash(h uint64) uint64 {
+	estimate := uint64(math.MaxUint64)
+	for row := range s.depth {
+		estimate = min(estimate, s.counts[row*s.width+nthHash(h, row)%s.width])
+	}
+	return estimate
+}
+
+// Total returns the number of occurrences added
+func (s *CountMinSketch) Total() uint64 {
+	return s.total
+}
+
+// Merge adds the occurrences counted by other. ErrIncompatibleSketch is returned when the dimensions differ
+func (s *CountMinSketch) Merge(other *CountMinSketch) error {
+	if s.width != other.width || s.depth != other.depth {
+		return ErrIncompatibleSketch
+	}
+	for i, c := range other.counts {
+		s.counts[i] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// TopK tracks the most frequent values of a stream ("top talkers"), counting them with a CountMinSketch.
+// Only the k best candidates are remembered, so values must be frequent early enough to be kept. It is not safe for concurrent use
+type TopK struct {
+	k      int
+	sketch *CountMinSketch
+	top    map[string]uint64
+}
+
+// NewTopK returns a tracker of the k most frequent values, counted by sketch
+func NewTopK(k int, sketch *CountMinSketch) *TopK {
+	return &TopK{k: k, sketch: sketch, top: make(map[string]uint64, k)}
+}
+
+// Add adds count occurrences of v
+func (t *TopK) Add(v string, count uint64) {
+	h := HashString64(v, 0)
+	t.sketch.AddHash(h, count)
+	estimate := t.sketch.EstimateHash(h)
+
+	if _, ok := t.top[v]; ok || len(t.top) < t.k {
+		t.top[v] = estimate
+		return
+	}
+
+	// replace the least frequent candidate if v is now more frequent
+	minValue, minCount := "", uint64(math.MaxUint64)
+	for candidate, c := range t.top {
+		if c < minCount || (c == minCount && candidate > minValue) {
+			minValue, minCount = candidate, c
+		}
+	}
+	if estimate > minCount {
+		delete(t.top, minValue)
+		t.top[v] = estimate
+	}
+}
+
+// Top returns the tracked values with their estimated count as Score, most frequent first
+func (t *TopK) Top() []Result {
+	results := make([]Result, 0, len(t.top))
+	for v, c := range t.top {
+		results = append(results, Result{Score: float64(c), Value: v})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].Value.(string) < results[j].Value.(string)
+		}
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
@@ -0,0 +1,66 @@
+package algo
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	s := NewCountMinSketchWithEstimates(0.001, 0.01)
+	for i := range 1000 {
+		s.AddString(strconv.Itoa(i), uint64(i%10+1))
+	}
+	s.Add([]byte("heavy"), 5000)
+
+	assert.Equal(t, uint64(5000+5500), s.Total())
+	assert.GreaterOrEqual(t, s.Estimate([]byte("heavy")), uint64(5000))
+	assert.InDelta(t, 5000, s.EstimateString("heavy"), 0.001*float64(s.Total()))
+	for i := range 1000 {
+		assert.GreaterOrEqual(t, s.EstimateString(strconv.Itoa(i)), uint64(i%10+1))
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		a := NewCountMinSketch(100, 4)
+		b := NewCountMinSketch(100, 4)
+		a.AddString("x", 3)
+		b.AddString("x", 4)
+		assert.NoError(t, a.Merge(b))
+		assert.Equal(t, uint64(7), a.EstimateString("x"))
+		assert.Equal(t, uint64(7), a.Total())
+
+		assert.ErrorIs(t, a.Merge(NewCountMinSketch(10, 4)), ErrIncompatibleSketch)
+	})
+
+	t.Run("estimates panic on invalid parameters", func(t *testing.T) {
+		for _, epsilon := range []float64{0, -0.1, math.NaN(), math.Inf(1), 1e-300} {
+			assert.Panics(t, func() { NewCountMinSketchWithEstimates(epsilon, 0.01) }, epsilon)
+		}
+		for _, delta := range []float64{0, -0.1, 1, 1.5, math.NaN()} {
+			assert.Panics(t, func() { NewCountMinSketchWithEstimates(0.01, delta) }, delta)
+		}
+	})
+}
+
+func TestTopK(t *testing.T) {
+	top := NewTopK(3, NewCountMinSketch(1000, 5))
+	for i := range 500 {
+		top.Add("noise "+strconv.Itoa(i), 1)
+		if i%2 == 0 {
+			top.Add("10.0.0.1", 2)
+		}
+		if i%5 == 0 {
+			top.Add("10.0.0.2", 3)
+		}
+		if i%10 == 0 {
+			top.Add("10.0.0.3", 1)
+		}
+	}
+
+	results := top.Top()
+	assert.Len(t, results, 3)
+	assert.Equal(t, []any{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, []any{results[0].Value, results[1].Value, results[2].Value})
+	assert.GreaterOrEqual(t, results[0].Score, 500.0)
+}
@@ -0,0 +1,666 @@
+package algo
+
+import "strings"
+
+// doubleMetaphoneMaxLen is the standard length of Double Metaphone codes
+const doubleMetaphoneMaxLen = 4
+
+// DoubleMetaphone returns the primary and alternate Double Metaphone codes of s (Lawrence Philips, 2000), 4 characters at most.
+// The alternate code accounts for non english (slavic, germanic, spanish, italian...) pronunciations and is often equal to the primary one.
+// 0 (zero) stands for the TH sound and X for SH
+func DoubleMetaphone(s string) (primary string, alternate string) {
+	value := []rune(strings.ToUpper(strings.TrimSpace(s)))
+	if len(value) == 0 {
+		return "", ""
+	}
+
+	dm := doubleMetaphone{value: value}
+	dm.slavoGermanic = dm.isSlavoGermanic()
+	dm.encode()
+	return dm.primary.String(), dm.alternate.String()
+}
+
+// DoubleMetaphonePrimary returns only the primary code of DoubleMetaphone
+func DoubleMetaphonePrimary(s string) string {
+	primary, _ := DoubleMetaphone(s)
+	return primary
+}
+
+type doubleMetaphone struct {
+	value         []rune
+	slavoGermanic bool
+	primary       strings.Builder
+	alternate     strings.Builder
+}
+
+func (dm *doubleMetaphone) isSlavoGermanic() bool {
+	v := string(dm.value)
+	return strings.ContainsRune(v, 'W') || strings.ContainsRune(v, 'K') || strings.Contains(v, "CZ") || strings.Contains(v, "WITZ")
+}
+
+func (dm *doubleMetaphone) isComplete() bool {
+	return dm.primary.Len() >= doubleMetaphoneMaxLen && dm.alternate.Len() >= doubleMetaphoneMaxLen
+}
+
+// add appends the same code to both the primary and alternate codes
+func (dm *doubleMetaphone) add(code string) {
+	dm.addBoth(code, code)
+}
+
+func (dm *doubleMetaphone) addBoth(primary string, alternate string) {
+	dm.addPrimary(primary)
+	dm.addAlternate(alternate)
+}
+
+func (dm *doubleMetaphone) addPrimary(code string) {
+	appendMax(&dm.primary, code)
+}
+
+func (dm *doubleMetaphone) addAlternate(code string) {
+	appendMax(&dm.alternate, code)
+}
+
+func appendMax(b *strings.Builder, code string) {
+	if room := doubleMetaphoneMaxLen - b.Len(); room > 0 {
+		b.WriteString(code[:min(room, len(code))])
+	}
+}
+
+// at returns the rune at i, or 0 when out of bounds
+func (dm *doubleMetaphone) at(i int) rune {
+	if i < 0 || i >= len(dm.value) {
+		return 0
+	}
+	return dm.value[i]
+}
+
+// contains returns whether the length runes at start are equal to any of the (ascii) criteria
+func (dm *doubleMetaphone) contains(start int, length int, criteria ...string) bool {
+	if start < 0 || start+length > len(dm.value) {
+		return false
+	}
+
+	for _, c := range criteria {
+		if len(c) != length {
+			continue
+		}
+		match := true
+		for i := 0; i < length; i++ {
+			if dm.value[start+i] != rune(c[i]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func (dm *doubleMetaphone) isVowel(i int) bool {
+	switch dm.at(i) {
+	case 'A', 'E', 'I', 'O', 'U', 'Y':
+		return true
+	}
+	return false
+}
+
+// skipDouble returns the index after the letter at index, skipping it twice when it is doubled
+func (dm *doubleMetaphone) skipDouble(index int) int {
+	if dm.at(index+1) == dm.at(index) {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) encode() {
+	index := 0
+	if dm.contains(0, 2, "GN", "KN", "PN", "WR", "PS") {
+		// silent first letter
+		index = 1
+	}
+
+	last := len(dm.value) - 1
+	for !dm.isComplete() && index <= last {
+		switch dm.value[index] {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			// vowels are only coded when they start the word
+			if index == 0 {
+				dm.add("A")
+			}
+			index++
+		case 'B':
+			dm.add("P")
+			index = dm.skipDouble(index)
+		case 'Ç':
+			dm.add("S")
+			index++
+		case 'C':
+			index = dm.handleC(index)
+		case 'D':
+			index = dm.handleD(index)
+		case 'F':
+			dm.add("F")
+			index = dm.skipDouble(index)
+		case 'G':
+			index = dm.handleG(index)
+		case 'H':
+			index = dm.handleH(index)
+		case 'J':
+			index = dm.handleJ(index)
+		case 'K':
+			dm.add("K")
+			index = dm.skipDouble(index)
+		case 'L':
+			index = dm.handleL(index)
+		case 'M':
+			dm.add("M")
+			if dm.conditionM0(index) {
+				index += 2
+			} else {
+				index++
+			}
+		case 'N':
+			dm.add("N")
+			index = dm.skipDouble(index)
+		case 'Ñ':
+			dm.add("N")
+			index++
+		case 'P':
+			index = dm.handleP(index)
+		case 'Q':
+			dm.add("K")
+			index = dm.skipDouble(index)
+		case 'R':
+			index = dm.handleR(index)
+		case 'S':
+			index = dm.handleS(index)
+		case 'T':
+			index = dm.handleT(index)
+		case 'V':
+			dm.add("F")
+			index = dm.skipDouble(index)
+		case 'W':
+			index = dm.handleW(index)
+		case 'X':
+			index = dm.handleX(index)
+		case 'Z':
+			index = dm.handleZ(index)
+		default:
+			index++
+		}
+	}
+}
+
+func (dm *doubleMetaphone) handleC(index int) int {
+	switch {
+	case dm.conditionC0(index):
+		// various germanic, like bacher or macher
+		dm.add("K")
+		return index + 2
+	case index == 0 && dm.contains(index, 6, "CAESAR"):
+		dm.add("S")
+		return index + 2
+	case dm.contains(index, 2, "CH"):
+		return dm.handleCH(index)
+	case dm.contains(index, 2, "CZ") && !dm.contains(index-2, 4, "WICZ"):
+		// czerny
+		dm.addBoth("S", "X")
+		return index + 2
+	case dm.contains(index+1, 3, "CIA"):
+		// focaccia
+		dm.add("X")
+		return index + 3
+	case dm.contains(index, 2, "CC") && !(index == 1 && dm.at(0) == 'M'):
+		// double C, but not if like mcclellan
+		return dm.handleCC(index)
+	case dm.contains(index, 2, "CK", "CG", "CQ"):
+		dm.add("K")
+		return index + 2
+	case dm.contains(index, 2, "CI", "CE", "CY"):
+		// italian vs english
+		if dm.contains(index, 3, "CIO", "CIE", "CIA") {
+			dm.addBoth("S", "X")
+		} else {
+			dm.add("S")
+		}
+		return index + 2
+	}
+
+	dm.add("K")
+	switch {
+	case dm.contains(index+1, 2, " C", " Q", " G"):
+		// mac caffrey, mac gregor
+		return index + 3
+	case dm.contains(index+1, 1, "C", "K", "Q") && !dm.contains(index+1, 2, "CE", "CI"):
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleCC(index int) int {
+	if dm.contains(index+2, 1, "I", "E", "H") && !dm.contains(index+2, 2, "HU") {
+		if (index == 1 && dm.at(index-1) == 'A') || dm.contains(index-1, 5, "UCCEE", "UCCES") {
+			// accident, accede, succeed
+			dm.add("KS")
+		} else {
+			// bacchus, bellocchio
+			dm.add("X")
+		}
+		return index + 3
+	}
+
+	// pierce's rule
+	dm.add("K")
+	return index + 2
+}
+
+func (dm *doubleMetaphone) handleCH(index int) int {
+	switch {
+	case index > 0 && dm.contains(index, 4, "CHAE"):
+		// michael
+		dm.addBoth("K", "X")
+	case dm.conditionCH0(index), dm.conditionCH1(index):
+		// greek roots like chemistry, chorus, and germanic ones
+		dm.add("K")
+	case index > 0:
+		if dm.contains(0, 2, "MC") {
+			// mchugh
+			dm.add("K")
+		} else {
+			dm.addBoth("X", "K")
+		}
+	default:
+		dm.add("X")
+	}
+	return index + 2
+}
+
+func (dm *doubleMetaphone) handleD(index int) int {
+	switch {
+	case dm.contains(index, 2, "DG"):
+		if dm.contains(index+2, 1, "I", "E", "Y") {
+			// edge
+			dm.add("J")
+			return index + 3
+		}
+		// edgar
+		dm.add("TK")
+		return index + 2
+	case dm.contains(index, 2, "DT", "DD"):
+		dm.add("T")
+		return index + 2
+	}
+	dm.add("T")
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleG(index int) int {
+	switch {
+	case dm.at(index+1) == 'H':
+		return dm.handleGH(index)
+	case dm.at(index+1) == 'N':
+		switch {
+		case index == 1 && dm.isVowel(0) && !dm.slavoGermanic:
+			dm.addBoth("KN", "N")
+		case !dm.contains(index+2, 2, "EY") && dm.at(index+1) != 'Y' && !dm.slavoGermanic:
+			// not like cagney
+			dm.addBoth("N", "KN")
+		default:
+			dm.add("KN")
+		}
+		return index + 2
+	case dm.contains(index+1, 2, "LI") && !dm.slavoGermanic:
+		// tagliaro
+		dm.addBoth("KL", "L")
+		return index + 2
+	case index == 0 && (dm.at(index+1) == 'Y' || dm.contains(index+1, 2, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		// -ges-, -gep-, -gel-, -gie- at the beginning
+		dm.addBoth("K", "J")
+		return index + 2
+	case (dm.contains(index+1, 2, "ER") || dm.at(index+1) == 'Y') &&
+		!dm.contains(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!dm.contains(index-1, 1, "E", "I") &&
+		!dm.contains(index-1, 3, "RGY", "OGY"):
+		// -ger-, -gy-
+		dm.addBoth("K", "J")
+		return index + 2
+	case dm.contains(index+1, 1, "E", "I", "Y") || dm.contains(index-1, 4, "AGGI", "OGGI"):
+		// italian biaggi
+		switch {
+		case dm.contains(0, 4, "VAN ", "VON ") || dm.contains(0, 3, "SCH") || dm.contains(index+1, 2, "ET"):
+			// obvious germanic
+			dm.add("K")
+		case dm.contains(index+1, 3, "IER"):
+			dm.add("J")
+		default:
+			dm.addBoth("J", "K")
+		}
+		return index + 2
+	case dm.at(index+1) == 'G':
+		dm.add("K")
+		return index + 2
+	}
+	dm.add("K")
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleGH(index int) int {
+	switch {
+	case index > 0 && !dm.isVowel(index-1):
+		dm.add("K")
+	case index == 0:
+		// ghislane, ghiradelli
+		if dm.at(index+2) == 'I' {
+			dm.add("J")
+		} else {
+			dm.add("K")
+		}
+	case (index > 1 && dm.contains(index-2, 1, "B", "H", "D")) ||
+		(index > 2 && dm.contains(index-3, 1, "B", "H", "D")) ||
+		(index > 3 && dm.contains(index-4, 1, "B", "H")):
+		// parker's rule (with some further refinements), silent like hugh, bough, broughton
+	default:
+		if index > 2 && dm.at(index-1) == 'U' && dm.contains(index-3, 1, "C", "G", "L", "R", "T") {
+			// laugh, mclaughlin, cough, gough, rough, tough
+			dm.add("F")
+		} else if index > 0 && dm.at(index-1) != 'I' {
+			dm.add("K")
+		}
+	}
+	return index + 2
+}
+
+func (dm *doubleMetaphone) handleH(index int) int {
+	// only keep if first and before a vowel, or between 2 vowels
+	if (index == 0 || dm.isVowel(index-1)) && dm.isVowel(index+1) {
+		dm.add("H")
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleJ(index int) int {
+	if dm.contains(index, 4, "JOSE") || dm.contains(0, 4, "SAN ") {
+		// obvious spanish, jose, san jacinto
+		if (index == 0 && dm.at(index+4) == ' ') || len(dm.value) == 4 || dm.contains(0, 4, "SAN ") {
+			dm.add("H")
+		} else {
+			dm.addBoth("J", "H")
+		}
+		return index + 1
+	}
+
+	switch {
+	case index == 0:
+		// yankelovich, jankelowicz
+		dm.addBoth("J", "A")
+	case dm.isVowel(index-1) && !dm.slavoGermanic && (dm.at(index+1) == 'A' || dm.at(index+1) == 'O'):
+		// spanish pronunciation, like bajador
+		dm.addBoth("J", "H")
+	case index == len(dm.value)-1:
+		dm.addBoth("J", "")
+	case !dm.contains(index+1, 1, "L", "T", "K", "S", "N", "M", "B", "Z") && !dm.contains(index-1, 1, "S", "K", "L"):
+		dm.add("J")
+	}
+
+	if dm.at(index+1) == 'J' {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleL(index int) int {
+	if dm.at(index+1) == 'L' {
+		if dm.conditionL0(index) {
+			// spanish, like cabrillo or gallegos
+			dm.addPrimary("L")
+		} else {
+			dm.add("L")
+		}
+		return index + 2
+	}
+	dm.add("L")
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleP(index int) int {
+	if dm.at(index+1) == 'H' {
+		dm.add("F")
+		return index + 2
+	}
+
+	// also account for campbell and raspberry
+	dm.add("P")
+	if dm.contains(index+1, 1, "P", "B") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleR(index int) int {
+	if index == len(dm.value)-1 && !dm.slavoGermanic && dm.contains(index-2, 2, "IE") && !dm.contains(index-4, 2, "ME", "MA") {
+		// french, like rogier, but not hochmeier
+		dm.addAlternate("R")
+	} else {
+		dm.add("R")
+	}
+
+	if dm.at(index+1) == 'R' {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleS(index int) int {
+	switch {
+	case dm.contains(index-1, 3, "ISL", "YSL"):
+		// special cases island, isle, carlisle, carlysle
+		return index + 1
+	case index == 0 && dm.contains(index, 5, "SUGAR"):
+		// special case sugar-
+		dm.addBoth("X", "S")
+		return index + 1
+	case dm.contains(index, 2, "SH"):
+		if dm.contains(index+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			// germanic
+			dm.add("S")
+		} else {
+			dm.add("X")
+		}
+		return index + 2
+	case dm.contains(index, 3, "SIO", "SIA") || dm.contains(index, 4, "SIAN"):
+		// italian and armenian
+		if dm.slavoGermanic {
+			dm.add("S")
+		} else {
+			dm.addBoth("S", "X")
+		}
+		return index + 3
+	case (index == 0 && dm.contains(index+1, 1, "M", "N", "L", "W")) || dm.contains(index+1, 1, "Z"):
+		// german and anglicisations, like smith matching schmidt or snider matching schneider.
+		// Also -sz- in slavic languages, although in hungarian it is pronounced S
+		dm.addBoth("S", "X")
+		if dm.contains(index+1, 1, "Z") {
+			return index + 2
+		}
+		return index + 1
+	case dm.contains(index, 2, "SC"):
+		return dm.handleSC(index)
+	}
+
+	if index == len(dm.value)-1 && dm.contains(index-2, 2, "AI", "OI") {
+		// french, like resnais or artois
+		dm.addAlternate("S")
+	} else {
+		dm.add("S")
+	}
+	if dm.contains(index+1, 1, "S", "Z") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleSC(index int) int {
+	switch {
+	case dm.at(index+2) == 'H':
+		// schlesinger's rule
+		switch {
+		case dm.contains(index+3, 2, "OO", "ER", "EN", "UY", "ED", "EM"):
+			// dutch origin, like school or schooner
+			if dm.contains(index+3, 2, "ER", "EN") {
+				// schermerhorn, schenker
+				dm.addBoth("X", "SK")
+			} else {
+				dm.add("SK")
+			}
+		case index == 0 && !dm.isVowel(3) && dm.at(3) != 'W':
+			dm.addBoth("X", "S")
+		default:
+			dm.add("X")
+		}
+	case dm.contains(index+2, 1, "I", "E", "Y"):
+		dm.add("S")
+	default:
+		dm.add("SK")
+	}
+	return index + 3
+}
+
+func (dm *doubleMetaphone) handleT(index int) int {
+	switch {
+	case dm.contains(index, 4, "TION"):
+		dm.add("X")
+		return index + 3
+	case dm.contains(index, 3, "TIA", "TCH"):
+		dm.add("X")
+		return index + 3
+	case dm.contains(index, 2, "TH") || dm.contains(index, 3, "TTH"):
+		if dm.contains(index+2, 2, "OM", "AM") || dm.contains(0, 4, "VAN ", "VON ") || dm.contains(0, 3, "SCH") {
+			// special case thomas, thames or germanic
+			dm.add("T")
+		} else {
+			dm.addBoth("0", "T")
+		}
+		return index + 2
+	}
+
+	dm.add("T")
+	if dm.contains(index+1, 1, "T", "D") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleW(index int) int {
+	switch {
+	case dm.contains(index, 2, "WR"):
+		// can also be in the middle of a word
+		dm.add("R")
+		return index + 2
+	case index == 0 && (dm.isVowel(index+1) || dm.contains(index, 2, "WH")):
+		if dm.isVowel(index + 1) {
+			// wasserman should match vasserman
+			dm.addBoth("A", "F")
+		} else {
+			// need Uomo to match Womo
+			dm.add("A")
+		}
+	case (index == len(dm.value)-1 && dm.isVowel(index-1)) ||
+		dm.contains(index-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") ||
+		dm.contains(0, 3, "SCH"):
+		// arnow should match arnoff
+		dm.addAlternate("F")
+	case dm.contains(index, 4, "WICZ", "WITZ"):
+		// polish, like filipowicz
+		dm.addBoth("TS", "FX")
+		return index + 4
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleX(index int) int {
+	if index == 0 {
+		// xavier
+		dm.add("S")
+		return index + 1
+	}
+
+	isFrenchEnding := index == len(dm.value)-1 && (dm.contains(index-3, 3, "IAU", "EAU") || dm.contains(index-2, 2, "AU", "OU"))
+	if !isFrenchEnding {
+		// not breaux
+		dm.add("KS")
+	}
+	if dm.contains(index+1, 1, "C", "X") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) handleZ(index int) int {
+	if dm.at(index+1) == 'H' {
+		// chinese pinyin, like zhao
+		dm.add("J")
+		return index + 2
+	}
+
+	if dm.contains(index+1, 2, "ZO", "ZI", "ZA") || (dm.slavoGermanic && index > 0 && dm.at(index-1) != 'T') {
+		dm.addBoth("S", "TS")
+	} else {
+		dm.add("S")
+	}
+	if dm.at(index+1) == 'Z' {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (dm *doubleMetaphone) conditionC0(index int) bool {
+	switch {
+	case dm.contains(index, 4, "CHIA"):
+		return true
+	case index <= 1:
+		return false
+	case dm.isVowel(index - 2):
+		return false
+	case !dm.contains(index-1, 3, "ACH"):
+		return false
+	}
+	c := dm.at(index + 2)
+	return (c != 'I' && c != 'E') || dm.contains(index-2, 6, "BACHER", "MACHER")
+}
+
+func (dm *doubleMetaphone) conditionCH0(index int) bool {
+	if index != 0 {
+		return false
+	}
+	if !dm.contains(index+1, 5, "HARAC", "HARIS") && !dm.contains(index+1, 3, "HOR", "HYM", "HIA", "HEM") {
+		return false
+	}
+	return !dm.contains(0, 5, "CHORE")
+}
+
+func (dm *doubleMetaphone) conditionCH1(index int) bool {
+	return dm.contains(0, 4, "VAN ", "VON ") ||
+		dm.contains(0, 3, "SCH") ||
+		dm.contains(index-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		dm.contains(index+2, 1, "T", "S") ||
+		((dm.contains(index-1, 1, "A", "O", "U", "E") || index == 0) &&
+			(dm.contains(index+2, 1, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || index+1 == len(dm.value)-1))
+}
+
+func (dm *doubleMetaphone) conditionL0(index int) bool {
+	last := len(dm.value) - 1
+	if index == last-2 && dm.contains(index-1, 4, "ILLO", "ILLA", "ALLE") {
+		return true
+	}
+	return (dm.contains(last-1, 2, "AS", "OS") || dm.contains(last, 1, "A", "O")) && dm.contains(index-1, 4, "ALLE")
+}
+
+func (dm *doubleMetaphone) conditionM0(index int) bool {
+	if dm.at(index+1) == 'M' {
+		return true
+	}
+	return dm.contains(index-1, 3, "UMB") && (index+1 == len(dm.value)-1 || dm.contains(index+2, 2, "ER"))
+}
@@ -0,0 +1,47 @@
+package algo
+
+// Hashing utilities shared by the sketches and the LSH index.
+// Hashes are stable across processes and platforms (unlike hash/maphash), so that serialised sketches remain valid
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// Hash64 returns a 64 bits hash of data. Different seeds give independent hash functions
+func Hash64(data []byte, seed uint64) uint64 {
+	h := uint64(fnvOffset64) ^ Mix64(seed)
+	for _, b := range data {
+		h ^= uint64(b)
+		h *= fnvPrime64
+	}
+	return Mix64(h)
+}
+
+// HashString64 is Hash64 for strings, without converting them to bytes
+func HashString64(s string, seed uint64) uint64 {
+	h := uint64(fnvOffset64) ^ Mix64(seed)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return Mix64(h)
+}
+
+// Mix64 scrambles the bits of x (splitmix64 finalizer), so that close inputs give unrelated outputs.
+// FNV alone mixes its last bytes poorly, which matters when the high or low bits of a hash are used on their own
+func Mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// nthHash derives the i-th of many hash functions from a single hash (Kirsch-Mitzenmacher double hashing),
+// which is as good as independent functions for sketches while hashing the data only once
+func nthHash(h uint64, i uint64) uint64 {
+	h2 := Mix64(h^0x9e3779b97f4a7c15) | 1 // odd, so it never cycles early
+	return h + i*h2
+}
@@ -0,0 +1,16 @@
+package algo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash64(t *testing.T) {
+	assert.Equal(t, Hash64([]byte("gowtools"), 0), HashString64("gowtools", 0))
+	assert.NotEqual(t, HashString64("gowtools", 0), HashString64("gowtools", 1))
+	assert.NotEqual(t, HashString64("ab", 0), HashString64("ba", 0))
+
+	// stable across processes, so serialised sketches stay valid
+	assert.Equal(t, uint64(0x5692161d100b05e5), Mix64(1))
+}
@@ -0,0 +1,125 @@
+package algo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+var ErrIncompatibleSketch = errors.New("algo: sketches must have the same parameters")
+
+const hyperLogLogVersion = 1
+
+// HyperLogLog estimates the number of distinct values of a stream in a fixed amount of memory (2^precision bytes).
+// The standard error is about 1.04 / sqrt(2^precision), so 1.6% with the precision 12 (4KB).
+// It is not safe for concurrent use
+type HyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+// NewHyperLogLog returns an empty HyperLogLog. precision must be between 4 and 18
+func NewHyperLogLog(precision uint8) (*HyperLogLog, error) {
+	if precision < 4 || precision > 18 {
+		return nil, fmt.Errorf("algo: hyperloglog precision must be between 4 and 18, got %d", precision)
+	}
+	return &HyperLogLog{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+// Add adds data to the observed values
+func (h *HyperLogLog) Add(data []byte) {
+	h.AddHash(Hash64(data, 0))
+}
+
+// AddString adds s to the observed values
+func (h *HyperLogLog) AddString(s string) {
+	h.AddHash(HashString64(s, 0))
+}
+
+// AddHash adds an already hashed value, which must be well distributed over the 64 bits (see Hash64)
+func (h *HyperLogLog) AddHash(x uint64) {
+	// the first bits pick the register, which keeps the longest run of leading zeros seen in the remaining bits
+	idx := x >> (64 - h.precision)
+	rest := x<<h.precision | 1<<(h.precision-1) // the sentinel bit caps the run length
+	rank := uint8(bits.LeadingZeros64(rest)) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+// Count returns the estimated number of distinct values added
+func (h *HyperLogLog) Count() uint64 {
+	m := float64(len(h.registers))
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := hyperLogLogAlpha(len(h.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// small cardinalities are better estimated by linear counting
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+func hyperLogLogAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// Merge adds the values observed by other, as if they had been added to h.
+// ErrIncompatibleSketch is returned when the precisions differ
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.precision != other.precision {
+		return ErrIncompatibleSketch
+	}
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 2+len(h.registers))
+	data = append(data, hyperLogLogVersion, h.precision)
+	return append(data, h.registers...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != hyperLogLogVersion {
+		return errors.New("algo: invalid hyperloglog data")
+	}
+
+	decoded, err := NewHyperLogLog(data[1])
+	if err != nil {
+		return err
+	}
+	if len(data)-2 != len(decoded.registers) {
+		return errors.New("algo: invalid hyperloglog data length")
+	}
+	copy(decoded.registers, data[2:])
+
+	*h = *decoded
+	return nil
+}
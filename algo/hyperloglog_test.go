@@ -0,0 +1,71 @@
+package algo
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	_, err := NewHyperLogLog(3)
+	assert.Error(t, err)
+
+	h, err := NewHyperLogLog(14)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), h.Count())
+
+	t.Run("small cardinality", func(t *testing.T) {
+		h, _ := NewHyperLogLog(14)
+		for i := range 10 {
+			h.AddString("value " + strconv.Itoa(i))
+			h.AddString("value " + strconv.Itoa(i)) // duplicates are not counted
+		}
+		assert.Equal(t, uint64(10), h.Count())
+	})
+
+	t.Run("large cardinality", func(t *testing.T) {
+		h, _ := NewHyperLogLog(14)
+		n := 200000
+		for i := range n {
+			h.Add([]byte(strconv.Itoa(i)))
+		}
+		assert.InEpsilon(t, n, h.Count(), 0.03)
+	})
+
+	t.Run("merge is the same as adding everything", func(t *testing.T) {
+		a, _ := NewHyperLogLog(12)
+		b, _ := NewHyperLogLog(12)
+		all, _ := NewHyperLogLog(12)
+		for i := range 50000 {
+			v := strconv.Itoa(i)
+			if i%2 == 0 {
+				a.AddString(v)
+			} else {
+				b.AddString(v)
+			}
+			all.AddString(v)
+		}
+		assert.NoError(t, a.Merge(b))
+		assert.Equal(t, all.Count(), a.Count())
+
+		other, _ := NewHyperLogLog(10)
+		assert.ErrorIs(t, a.Merge(other), ErrIncompatibleSketch)
+	})
+
+	t.Run("binary round trip", func(t *testing.T) {
+		h, _ := NewHyperLogLog(10)
+		for i := range 1000 {
+			h.AddString(strconv.Itoa(i))
+		}
+		data, err := h.MarshalBinary()
+		assert.NoError(t, err)
+
+		decoded := &HyperLogLog{}
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, h.Count(), decoded.Count())
+
+		assert.Error(t, decoded.UnmarshalBinary(data[:10]))
+		assert.Error(t, decoded.UnmarshalBinary([]byte{9, 10}))
+	})
+}
@@ -1,27 +1,92 @@
 package algo
 
-func Jaccard(a []int, b []int) float64 {
+import "cmp"
+
+// Jaccard returns |A∩B| / |A∪B| of the distinct values of a and b, so duplicated elements are only counted once.
+// Two empty slices are identical (1). Complexity is O(n+m), see JaccardSorted to avoid building sets on big sorted inputs
+func Jaccard[T comparable](a []T, b []T) float64 {
 	if len(a) == 0 && len(b) == 0 {
 		return 1
 	}
+	return JaccardSet(NewSet(a...), NewSet(b...))
+}
 
-	if len(a) > len(b) {
-		// make sure 'a' is always the smaller set so we iterate less
-		a, b = b, a
+// JaccardSet returns |A∩B| / |A∪B|. Two empty sets are identical (1)
+func JaccardSet[T comparable](a Set[T], b Set[T]) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
 	}
 
-	intersect := map[int]uint8{}
-	for _, iVal := range a {
-		for _, jVal := range b {
-			if iVal == jVal {
-				intersect[iVal] = 1
-				break
-			}
+	intersect := intersectionSize(a, b)
+	union := (len(a) + len(b)) - intersect
+	return float64(intersect) / float64(union)
+}
+
+// JaccardSorted computes the same value as Jaccard on slices sorted in ascending order, by merging them without allocating.
+// Duplicated elements are only counted once. The result is undefined if the slices are not sorted
+func JaccardSorted[T cmp.Ordered](a []T, b []T) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	intersect := 0
+	union := 0
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		var c int
+		switch {
+		case i == len(a):
+			c = 1
+		case j == len(b):
+			c = -1
+		default:
+			c = cmp.Compare(a[i], b[j])
+		}
+
+		union++
+		var v T
+		switch {
+		case c < 0:
+			v = a[i]
+		case c > 0:
+			v = b[j]
+		default:
+			v = a[i]
+			intersect++
+		}
+
+		// skip every duplicate of the current value on both sides
+		for i < len(a) && a[i] == v {
+			i++
+		}
+		for j < len(b) && b[j] == v {
+			j++
 		}
 	}
 
-	union := (len(a) + len(b)) - len(intersect)
-	return float64(len(intersect)) / float64(union)
+	return float64(intersect) / float64(union)
+}
+
+// WeightedJaccard returns sum(min(a,b)) / sum(max(a,b)) over all the keys of a and b, a missing key having a weight of 0.
+// Weights must not be negative. Two empty (or all zero) inputs are identical (1)
+func WeightedJaccard[T comparable](a map[T]float64, b map[T]float64) float64 {
+	sumMin := 0.0
+	sumMax := 0.0
+	for k, wa := range a {
+		wb := b[k]
+		sumMin += min(wa, wb)
+		sumMax += max(wa, wb)
+	}
+	for k, wb := range b {
+		if _, ok := a[k]; !ok {
+			sumMax += wb
+		}
+	}
+
+	if sumMax == 0 {
+		return 1
+	}
+	return sumMin / sumMax
 }
 
 // JaccardFast uses a map to find intersecting elements, reducing the complexity of the olgarithm to O(n) instead of O(n^2)
@@ -40,4 +105,3 @@ func JaccardFast(a []uint16, b map[uint16]uint8) float64 {
 	union := (len(a) + len(b)) - len(intersect)
 	return float64(len(intersect)) / float64(union)
 }
-
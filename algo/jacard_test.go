@@ -0,0 +1,64 @@
+package algo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJaccard(t *testing.T) {
+	var tests = []struct {
+		a        []int
+		b        []int
+		expected float64
+		testName string
+	}{
+		{a: []int{}, b: []int{}, expected: 1, testName: "both empty"},
+		{a: []int{1}, b: []int{}, expected: 0, testName: "one empty"},
+		{a: []int{1, 2, 3}, b: []int{3, 2, 1}, expected: 1, testName: "same values"},
+		{a: []int{1, 2}, b: []int{3, 4}, expected: 0, testName: "disjoint"},
+		{a: []int{1, 2, 3}, b: []int{2, 3, 4}, expected: 0.5, testName: "partial"},
+		{a: []int{1, 1, 2, 2}, b: []int{2, 3, 3}, expected: 1.0 / 3, testName: "duplicates counted once"},
+	}
+
+	for _, ut := range tests {
+		msg := fmt.Sprintf("Test %s", ut.testName)
+		assert.InDelta(t, ut.expected, Jaccard(ut.a, ut.b), 1e-9, msg)
+		assert.InDelta(t, ut.expected, Jaccard(ut.b, ut.a), 1e-9, msg)
+		assert.InDelta(t, ut.expected, JaccardSet(NewSet(ut.a...), NewSet(ut.b...)), 1e-9, msg)
+	}
+
+	t.Run("strings", func(t *testing.T) {
+		assert.InDelta(t, 0.5, Jaccard([]string{"ab", "bc", "cd"}, []string{"bc", "cd", "de"}), 1e-9)
+	})
+}
+
+func TestJaccardSorted(t *testing.T) {
+	var tests = []struct {
+		a        []int
+		b        []int
+		testName string
+	}{
+		{a: []int{}, b: []int{}, testName: "both empty"},
+		{a: []int{1, 2}, b: []int{}, testName: "one empty"},
+		{a: []int{1, 2, 3}, b: []int{2, 3, 4}, testName: "partial"},
+		{a: []int{1, 1, 2, 2, 5}, b: []int{2, 3, 3, 5, 5}, testName: "duplicates"},
+		{a: []int{1, 2}, b: []int{3, 4, 5, 6}, testName: "disjoint"},
+	}
+
+	for _, ut := range tests {
+		msg := fmt.Sprintf("Test %s", ut.testName)
+		assert.InDelta(t, Jaccard(ut.a, ut.b), JaccardSorted(ut.a, ut.b), 1e-9, msg)
+		assert.InDelta(t, Jaccard(ut.a, ut.b), JaccardSorted(ut.b, ut.a), 1e-9, msg)
+	}
+}
+
+func TestWeightedJaccard(t *testing.T) {
+	a := map[string]float64{"x": 1, "y": 2}
+	b := map[string]float64{"x": 2, "z": 1}
+	// min: x=1 -> 1, max: x=2 + y=2 + z=1 -> 5
+	assert.InDelta(t, 0.2, WeightedJaccard(a, b), 1e-9)
+	assert.InDelta(t, 1.0, WeightedJaccard(a, a), 1e-9)
+	assert.InDelta(t, 1.0, WeightedJaccard(map[string]float64{}, map[string]float64{}), 1e-9)
+}
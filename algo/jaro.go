@@ -0,0 +1,99 @@
+package algo
+
+import "unicode/utf8"
+
+// Jaro returns the Jaro similarity of a and b, from 0 (nothing in common) to 1 (identical).
+// Runes only match when they are close enough to each other, and out of order matches count as transpositions
+func Jaro(a string, b string) float64 {
+	var bufA, bufB [stackRunes]rune
+	ra := appendRunes(bufA[:0], a)
+	rb := appendRunes(bufB[:0], b)
+
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	var matchedBufA, matchedBufB [stackRunes]bool
+	matchedA := matchedBufA[:0]
+	for range ra {
+		matchedA = append(matchedA, false)
+	}
+	matchedB := matchedBufB[:0]
+	for range rb {
+		matchedB = append(matchedB, false)
+	}
+
+	matches := 0
+	for i := range ra {
+		start := max(0, i-window)
+		end := min(len(rb), i+window+1)
+		for j := start; j < end; j++ {
+			if matchedB[j] || ra[i] != rb[j] {
+				continue
+			}
+			matchedA[i] = true
+			matchedB[j] = true
+			matches++
+			break
+		}
+	}
+
+	if matches == 0 {
+		return 0
+	}
+
+	// count the matched runes that are not in the same order in both strings
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+// JaroWinkler boosts the Jaro similarity of strings sharing a common prefix (up to 4 runes), which suits names well.
+// As in Winkler's original definition, the boost only applies when the Jaro similarity is above 0.7
+func JaroWinkler(a string, b string) float64 {
+	const (
+		boostThreshold = 0.7
+		prefixScale    = 0.1
+		maxPrefix      = 4
+	)
+
+	similarity := Jaro(a, b)
+	if similarity <= boostThreshold {
+		return similarity
+	}
+
+	prefix := 0
+	for prefix < maxPrefix && a != "" && b != "" {
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			break
+		}
+		prefix++
+		a, b = a[sizeA:], b[sizeB:]
+	}
+
+	return similarity + float64(prefix)*prefixScale*(1-similarity)
+}
@@ -0,0 +1,33 @@
+package algo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJaroWinkler(t *testing.T) {
+	// reference values from Winkler's paper and the usual literature examples
+	var tests = []struct {
+		a           string
+		b           string
+		jaro        float64
+		jaroWinkler float64
+	}{
+		{"MARTHA", "MARHTA", 0.944, 0.961},
+		{"DWAYNE", "DUANE", 0.822, 0.840},
+		{"DIXON", "DICKSONX", 0.767, 0.813},
+		{"", "", 1, 1},
+		{"ABC", "", 0, 0},
+		{"ABC", "XYZ", 0, 0},
+		{"CRÈME", "CRÈME", 1, 1},
+	}
+
+	for _, ut := range tests {
+		msg := fmt.Sprintf("Test %q %q", ut.a, ut.b)
+		assert.InDelta(t, ut.jaro, Jaro(ut.a, ut.b), 0.001, msg)
+		assert.InDelta(t, ut.jaro, Jaro(ut.b, ut.a), 0.001, msg)
+		assert.InDelta(t, ut.jaroWinkler, JaroWinkler(ut.a, ut.b), 0.001, msg)
+	}
+}
@@ -0,0 +1,208 @@
+package algo
+
+import (
+	"container/heap"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Result is a value found by a similarity search, along its score. lsh.LSHResult has the same shape
+type Result struct {
+	Score float64
+	Value any
+}
+
+// Metric is how a VectorIndex compares vectors
+type Metric int
+
+const (
+	// MetricCosine scores with the cosine similarity, highest first. Zero vectors are never returned
+	MetricCosine Metric = iota
+	// MetricEuclidean scores with the euclidean distance, lowest first
+	MetricEuclidean
+)
+
+// minRowsPerWorker avoids spawning goroutines that would cost more than the rows they scan
+const minRowsPerWorker = 1024
+
+// VectorIndex is an exact (brute force) k-nearest-neighbour index over vectors of the same dimension.
+// It scans every vector on search, in parallel, which is fine for small datasets and as a ground truth for LSH.
+// Adding is not safe for concurrent use, searching is
+type VectorIndex[T Number] struct {
+	metric  Metric
+	values  []any
+	vectors [][]T
+}
+
+// NewVectorIndex returns an empty index comparing vectors with metric
+func NewVectorIndex[T Number](metric Metric) *VectorIndex[T] {
+	return &VectorIndex[T]{metric: metric}
+}
+
+// Add indexes vector, value being what searches return for it (an ID or a pointer preferably).
+// ErrLengthMismatch is returned when the vector dimension differs from the ones already added
+func (x *VectorIndex[T]) Add(value any, vector []T) error {
+	if len(x.vectors) > 0 && len(vector) != len(x.vectors[0]) {
+		return ErrLengthMismatch
+	}
+	x.values = append(x.values, value)
+	x.vectors = append(x.vectors, vector)
+	return nil
+}
+
+// Len returns the number of indexed vectors
+func (x *VectorIndex[T]) Len() int {
+	return len(x.vectors)
+}
+
+// Search returns the k nearest vectors to query, best first (see Metric).
+// ErrLengthMismatch is returned when query does not have the indexed vectors' dimension,
+// and ErrZeroVector for a zero query with MetricCosine
+func (x *VectorIndex[T]) Search(query []T, k int) ([]Result, error) {
+	if len(x.vectors) > 0 && len(query) != len(x.vectors[0]) {
+		return nil, ErrLengthMismatch
+	}
+
+	switch x.metric {
+	case MetricEuclidean:
+		return topK(len(x.vectors), k, lowerIsBetter, func(i int) (float64, bool) {
+			d, _ := EuclideanDistance(query, x.vectors[i])
+			return d, true
+		}, x.values), nil
+	default:
+		if Norm(query) == 0 {
+			return nil, ErrZeroVector
+		}
+		return topK(len(x.vectors), k, higherIsBetter, func(i int) (float64, bool) {
+			s, err := Cosine(query, x.vectors[i])
+			return s, err == nil
+		}, x.values), nil
+	}
+}
+
+// SetIndex is an exact (brute force) k-nearest-neighbour index over sets, compared with the Jaccard similarity.
+// Adding is not safe for concurrent use, searching is
+type SetIndex[T comparable] struct {
+	values []any
+	sets   []Set[T]
+}
+
+// NewSetIndex returns an empty set index
+func NewSetIndex[T comparable]() *SetIndex[T] {
+	return &SetIndex[T]{}
+}
+
+// Add indexes s, value being what searches return for it (an ID or a pointer preferably)
+func (x *SetIndex[T]) Add(value any, s Set[T]) {
+	x.values = append(x.values, value)
+	x.sets = append(x.sets, s)
+}
+
+// Len returns the number of indexed sets
+func (x *SetIndex[T]) Len() int {
+	return len(x.sets)
+}
+
+// Search returns the k sets with the highest Jaccard similarity to query, best first
+func (x *SetIndex[T]) Search(query Set[T], k int) []Result {
+	return topK(len(x.sets), k, higherIsBetter, func(i int) (float64, bool) {
+		return JaccardSet(query, x.sets[i]), true
+	}, x.values)
+}
+
+func higherIsBetter(a float64, b float64) bool { return a > b }
+func lowerIsBetter(a float64, b float64) bool  { return a < b }
+
+// topK scores the n rows in parallel and keeps the k best according to better. Rows for which score returns false are skipped.
+// Ties are broken by row order, so results are deterministic
+func topK(n int, k int, better func(a, b float64) bool, score func(i int) (float64, bool), values []any) []Result {
+	if k <= 0 || n == 0 {
+		return []Result{}
+	}
+
+	workers := min(runtime.GOMAXPROCS(0), (n+minRowsPerWorker-1)/minRowsPerWorker)
+	rowsPerWorker := (n + workers - 1) / workers
+
+	// each worker keeps its own bounded heap, merged once they are all done
+	heaps := make([]*boundedHeap, workers)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := range workers {
+		go func(w int) {
+			defer wg.Done()
+			h := newBoundedHeap(k, better)
+			for i := w * rowsPerWorker; i < min(n, (w+1)*rowsPerWorker); i++ {
+				if s, ok := score(i); ok {
+					h.offer(scoredRow{row: i, score: s})
+				}
+			}
+			heaps[w] = h
+		}(w)
+	}
+	wg.Wait()
+
+	merged := newBoundedHeap(k, better)
+	for _, h := range heaps {
+		for _, r := range h.rows {
+			merged.offer(r)
+		}
+	}
+
+	rows := merged.rows
+	sort.Slice(rows, func(i, j int) bool {
+		return merged.isBetter(rows[i], rows[j])
+	})
+
+	results := make([]Result, len(rows))
+	for i, r := range rows {
+		results[i] = Result{Score: r.score, Value: values[r.row]}
+	}
+	return results
+}
+
+type scoredRow struct {
+	row   int
+	score float64
+}
+
+// boundedHeap keeps the k best rows, with the worst of them on top so it can be replaced quickly
+type boundedHeap struct {
+	k      int
+	better func(a, b float64) bool
+	rows   []scoredRow
+}
+
+func newBoundedHeap(k int, better func(a, b float64) bool) *boundedHeap {
+	return &boundedHeap{k: k, better: better, rows: make([]scoredRow, 0, k)}
+}
+
+func (h *boundedHeap) isBetter(a scoredRow, b scoredRow) bool {
+	if a.score == b.score {
+		return a.row < b.row
+	}
+	return h.better(a.score, b.score)
+}
+
+func (h *boundedHeap) offer(r scoredRow) {
+	if len(h.rows) < h.k {
+		heap.Push(h, r)
+		return
+	}
+	if h.isBetter(r, h.rows[0]) {
+		h.rows[0] = r
+		heap.Fix(h, 0)
+	}
+}
+
+// heap.Interface, the "smallest" element being the worst row
+
+func (h *boundedHeap) Len() int           { return len(h.rows) }
+func (h *boundedHeap) Less(i, j int) bool { return h.isBetter(h.rows[j], h.rows[i]) }
+func (h *boundedHeap) Swap(i, j int)      { h.rows[i], h.rows[j] = h.rows[j], h.rows[i] }
+func (h *boundedHeap) Push(x any)         { h.rows = append(h.rows, x.(scoredRow)) }
+func (h *boundedHeap) Pop() any {
+	last := h.rows[len(h.rows)-1]
+	h.rows = h.rows[:len(h.rows)-1]
+	return last
+}
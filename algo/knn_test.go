@@ -0,0 +1,90 @@
+package algo
+
+import (
+	"math/rand/v2"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVectorIndex(t *testing.T) {
+	t.Run("cosine", func(t *testing.T) {
+		x := NewVectorIndex[int](MetricCosine)
+		assert.NoError(t, x.Add("a", []int{1, 0}))
+		assert.NoError(t, x.Add("b", []int{1, 1}))
+		assert.NoError(t, x.Add("c", []int{-1, 0}))
+		assert.NoError(t, x.Add("zero", []int{0, 0}))
+		assert.ErrorIs(t, x.Add("d", []int{1}), ErrLengthMismatch)
+		assert.Equal(t, 4, x.Len())
+
+		results, err := x.Search([]int{2, 0}, 10)
+		assert.NoError(t, err)
+		assert.Len(t, results, 3) // the zero vector cannot be compared
+		assert.Equal(t, []any{"a", "b", "c"}, []any{results[0].Value, results[1].Value, results[2].Value})
+		assert.InDelta(t, 1.0, results[0].Score, 1e-9)
+		assert.InDelta(t, -1.0, results[2].Score, 1e-9)
+
+		_, err = x.Search([]int{0, 0}, 1)
+		assert.ErrorIs(t, err, ErrZeroVector)
+		_, err = x.Search([]int{1}, 1)
+		assert.ErrorIs(t, err, ErrLengthMismatch)
+	})
+
+	t.Run("euclidean", func(t *testing.T) {
+		x := NewVectorIndex[float64](MetricEuclidean)
+		_ = x.Add(1, []float64{0, 0})
+		_ = x.Add(2, []float64{3, 4})
+		_ = x.Add(3, []float64{1, 1})
+
+		results, err := x.Search([]float64{0, 0}, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []Result{{Score: 0, Value: 1}, {Score: results[1].Score, Value: 3}}, results)
+	})
+
+	t.Run("empty index or k", func(t *testing.T) {
+		x := NewVectorIndex[int](MetricCosine)
+		results, err := x.Search([]int{1}, 3)
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+
+		_ = x.Add(1, []int{1})
+		results, _ = x.Search([]int{1}, 0)
+		assert.Empty(t, results)
+	})
+
+	t.Run("parallel scan matches a full sort", func(t *testing.T) {
+		x := NewVectorIndex[float32](MetricEuclidean)
+		distances := []float64{}
+		for i := range 10000 {
+			v := []float32{rand.Float32(), rand.Float32(), rand.Float32()}
+			_ = x.Add(i, v)
+			d, _ := EuclideanDistance([]float32{0.5, 0.5, 0.5}, v)
+			distances = append(distances, d)
+		}
+		sort.Float64s(distances)
+
+		results, err := x.Search([]float32{0.5, 0.5, 0.5}, 25)
+		assert.NoError(t, err)
+		assert.Len(t, results, 25)
+		for i := range results {
+			assert.Equal(t, distances[i], results[i].Score)
+		}
+	})
+}
+
+func TestSetIndex(t *testing.T) {
+	x := NewSetIndex[string]()
+	x.Add("ab", NewSet("a", "b"))
+	x.Add("abc", NewSet("a", "b", "c"))
+	x.Add("xyz", NewSet("x", "y", "z"))
+	x.Add("ab again", NewSet("a", "b"))
+	assert.Equal(t, 4, x.Len())
+
+	results := x.Search(NewSet("a", "b"), 3)
+	assert.Equal(t, []Result{
+		{Score: 1, Value: "ab"},
+		{Score: 1, Value: "ab again"},
+		{Score: 2.0 / 3, Value: "abc"},
+	}, results)
+}
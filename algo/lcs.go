@@ -0,0 +1,44 @@
+package algo
+
+// LongestCommonSubsequence returns the length, in runes, of the longest sequence of runes found in both a and b in the same order,
+// not necessarily contiguous
+func LongestCommonSubsequence(a string, b string) int {
+	var bufA, bufB [stackRunes]rune
+	ra := appendRunes(bufA[:0], a)
+	rb := appendRunes(bufB[:0], b)
+
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	var rowBuf [stackRunes + 1]int
+	row := rowBuf[:0]
+	for range len(rb) + 1 {
+		row = append(row, 0)
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		diagonal := 0
+		for j := 1; j <= len(rb); j++ {
+			above := row[j]
+			if ra[i-1] == rb[j-1] {
+				row[j] = diagonal + 1
+			} else {
+				row[j] = max(above, row[j-1])
+			}
+			diagonal = above
+		}
+	}
+
+	return row[len(rb)]
+}
+
+// LCSSimilarity normalizes the longest common subsequence to a similarity from 0 to 1: 2*lcs / (len(a)+len(b)) in runes.
+// Two empty strings are identical (1)
+func LCSSimilarity(a string, b string) float64 {
+	total := runeCount(a) + runeCount(b)
+	if total == 0 {
+		return 1
+	}
+	return 2 * float64(LongestCommonSubsequence(a, b)) / float64(total)
+}
@@ -0,0 +1,16 @@
+package algo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongestCommonSubsequence(t *testing.T) {
+	assert.Equal(t, 4, LongestCommonSubsequence("ABCBDAB", "BDCABA"))
+	assert.Equal(t, 0, LongestCommonSubsequence("", "abc"))
+	assert.Equal(t, 2, LongestCommonSubsequence("日本語", "日語"))
+
+	assert.InDelta(t, 1.0, LCSSimilarity("", ""), 1e-9)
+	assert.InDelta(t, 8.0/13, LCSSimilarity("ABCBDAB", "BDCABA"), 1e-9)
+}
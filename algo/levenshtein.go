@@ -0,0 +1,137 @@
+package algo
+
+// stackRunes is the size of the buffers the string functions try to keep on the stack, longer inputs are heap allocated
+const stackRunes = 64
+
+// appendRunes decodes s into buf, avoiding an allocation when s fits in buf's capacity
+func appendRunes(buf []rune, s string) []rune {
+	for _, r := range s {
+		buf = append(buf, r)
+	}
+	return buf
+}
+
+// Levenshtein returns the minimum number of single rune insertions, deletions or substitutions to change a into b
+func Levenshtein(a string, b string) int {
+	d, _ := LevenshteinMax(a, b, -1)
+	return d
+}
+
+// LevenshteinMax computes the Levenshtein distance, but stops as soon as it is known to exceed maxDistance.
+// The returned bool is false when the distance is over maxDistance, in which case the returned distance is maxDistance+1.
+// A negative maxDistance means no limit
+func LevenshteinMax(a string, b string, maxDistance int) (int, bool) {
+	var bufA, bufB [stackRunes]rune
+	ra := appendRunes(bufA[:0], a)
+	rb := appendRunes(bufB[:0], b)
+
+	// make sure 'b' is always the shorter one, so the row is as small as possible
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	if maxDistance >= 0 && len(ra)-len(rb) > maxDistance {
+		return maxDistance + 1, false
+	}
+
+	var rowBuf [stackRunes + 1]int
+	row := rowBuf[:0]
+	for j := 0; j <= len(rb); j++ {
+		row = append(row, j)
+	}
+
+	// row holds the distances of the previous prefix of a against every prefix of b
+	for i := 1; i <= len(ra); i++ {
+		diagonal := row[0]
+		row[0] = i
+		rowMin := row[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			above := row[j]
+			row[j] = min(above+1, row[j-1]+1, diagonal+cost)
+			diagonal = above
+			rowMin = min(rowMin, row[j])
+		}
+
+		// distances can only grow from a row to the next one
+		if maxDistance >= 0 && rowMin > maxDistance {
+			return maxDistance + 1, false
+		}
+	}
+
+	d := row[len(rb)]
+	if maxDistance >= 0 && d > maxDistance {
+		return maxDistance + 1, false
+	}
+	return d, true
+}
+
+// LevenshteinSimilarity normalizes the Levenshtein distance to a similarity from 0 (nothing in common) to 1 (identical)
+func LevenshteinSimilarity(a string, b string) float64 {
+	return editSimilarity(Levenshtein(a, b), a, b)
+}
+
+// DamerauLevenshtein is the Levenshtein distance where swapping two adjacent runes also counts as a single edit.
+// This is the optimal string alignment variant: a substring is never edited more than once, which is what typos need
+func DamerauLevenshtein(a string, b string) int {
+	var bufA, bufB [stackRunes]rune
+	ra := appendRunes(bufA[:0], a)
+	rb := appendRunes(bufB[:0], b)
+
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	// transpositions need to look 2 rows back
+	var bufs [3][stackRunes + 1]int
+	prevPrev := bufs[0][:0]
+	prev := bufs[1][:0]
+	cur := bufs[2][:0]
+	for j := 0; j <= len(rb); j++ {
+		prevPrev = append(prevPrev, 0)
+		prev = append(prev, j)
+		cur = append(cur, 0)
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prevPrev[j-2]+1)
+			}
+		}
+		prevPrev, prev, cur = prev, cur, prevPrev
+	}
+
+	return prev[len(rb)]
+}
+
+// DamerauLevenshteinSimilarity normalizes the Damerau-Levenshtein distance to a similarity from 0 to 1
+func DamerauLevenshteinSimilarity(a string, b string) float64 {
+	return editSimilarity(DamerauLevenshtein(a, b), a, b)
+}
+
+// editSimilarity is 1 - distance / longest rune length. Two empty strings are identical (1)
+func editSimilarity(distance int, a string, b string) float64 {
+	longest := max(runeCount(a), runeCount(b))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(distance)/float64(longest)
+}
+
+func runeCount(s string) int {
+	n := 0
+	for range s {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,84 @@
+package algo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshtein(t *testing.T) {
+	var tests = []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"ca", "ac", 2},
+		{"héllo", "hello", 1},
+		{"日本語", "日本", 1},
+		{strings.Repeat("a", 100), strings.Repeat("a", 99) + "b", 1},
+	}
+
+	for _, ut := range tests {
+		msg := fmt.Sprintf("Test %q %q", ut.a, ut.b)
+		assert.Equal(t, ut.expected, Levenshtein(ut.a, ut.b), msg)
+		assert.Equal(t, ut.expected, Levenshtein(ut.b, ut.a), msg)
+	}
+}
+
+func TestLevenshteinMax(t *testing.T) {
+	d, ok := LevenshteinMax("kitten", "sitting", 3)
+	assert.True(t, ok)
+	assert.Equal(t, 3, d)
+
+	d, ok = LevenshteinMax("kitten", "sitting", 2)
+	assert.False(t, ok)
+	assert.Equal(t, 3, d)
+
+	d, ok = LevenshteinMax("a", "abcdef", 2)
+	assert.False(t, ok)
+	assert.Equal(t, 3, d)
+}
+
+func TestDamerauLevenshtein(t *testing.T) {
+	var tests = []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"", "", 0},
+		{"ca", "ac", 1},
+		{"ca", "abc", 3}, // optimal string alignment does not edit a transposed substring again
+		{"brisson", "birsson", 1},
+		{"kitten", "sitting", 3},
+		{"日本語", "本日語", 1},
+	}
+
+	for _, ut := range tests {
+		msg := fmt.Sprintf("Test %q %q", ut.a, ut.b)
+		assert.Equal(t, ut.expected, DamerauLevenshtein(ut.a, ut.b), msg)
+		assert.Equal(t, ut.expected, DamerauLevenshtein(ut.b, ut.a), msg)
+	}
+}
+
+func TestEditSimilarities(t *testing.T) {
+	assert.InDelta(t, 1.0, LevenshteinSimilarity("", ""), 1e-9)
+	assert.InDelta(t, 1-3.0/7, LevenshteinSimilarity("kitten", "sitting"), 1e-9)
+	assert.InDelta(t, 0.5, DamerauLevenshteinSimilarity("ca", "ac"), 1e-9)
+	assert.InDelta(t, 0.0, LevenshteinSimilarity("abc", ""), 1e-9)
+}
+
+func TestLevenshteinAllocations(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		Levenshtein("william brisson", "wiliam brison")
+		DamerauLevenshtein("william brisson", "wiliam brison")
+		Jaro("william brisson", "wiliam brison")
+		LongestCommonSubsequence("william brisson", "wiliam brison")
+	})
+	assert.Equal(t, 0.0, allocs)
+}
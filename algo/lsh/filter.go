@@ -0,0 +1,94 @@
+package lsh
+
+import "sort"
+
+// Attributed is implemented by values exposing metadata attributes (country, status...), which searches can filter on
+type Attributed interface {
+	GetAttribute(name string) (string, bool)
+}
+
+// attributeIndex maps an attribute name to its values, and each value to the entries having it
+type attributeIndex map[string]map[string]map[*LshEntry]struct{}
+
+// IndexAttributes builds an index for the given attribute names. Searches filtering on them only collect the
+// bucket candidates having the wanted values, instead of calling GetAttribute on every candidate.
+// Entries whose value does not implement Attributed are not indexed. Calling it again for an attribute rebuilds its index
+func (l *LSH) IndexAttributes(names ...string) {
+	if l.attributes == nil {
+		l.attributes = attributeIndex{}
+	}
+
+	for _, name := range names {
+		byValue := map[string]map[*LshEntry]struct{}{}
+		for i := range l.Entries {
+			e := &l.Entries[i]
+			attributed, ok := e.Value.(Attributed)
+			if !ok {
+				continue
+			}
+			attrVal, ok := attributed.GetAttribute(name)
+			if !ok {
+				continue
+			}
+			if byValue[attrVal] == nil {
+				byValue[attrVal] = map[*LshEntry]struct{}{}
+			}
+			byValue[attrVal][e] = struct{}{}
+		}
+		l.attributes[name] = byValue
+	}
+}
+
+// indexedEntries returns the entries having the wanted value of every indexed attribute filtered by q.
+// narrowed is false when q filters on no indexed attribute, in which case every entry is a candidate
+func (l LSH) indexedEntries(q Query) (entries map[*LshEntry]struct{}, narrowed bool) {
+	var sets []map[*LshEntry]struct{}
+	for name, want := range q.Attributes {
+		if byValue, indexed := l.attributes[name]; indexed {
+			sets = append(sets, byValue[want])
+		}
+	}
+	if len(sets) == 0 {
+		return nil, false
+	}
+
+	// intersect starting from the smallest set
+	sort.Slice(sets, func(i, j int) bool {
+		return len(sets[i]) < len(sets[j])
+	})
+	entries = make(map[*LshEntry]struct{}, len(sets[0]))
+next:
+	for e := range sets[0] {
+		for _, set := range sets[1:] {
+			if _, ok := set[e]; !ok {
+				continue next
+			}
+		}
+		entries[e] = struct{}{}
+	}
+	return entries, true
+}
+
+// matchesFilters returns whether the entry satisfies both the attribute and the predicate filters of q.
+// Indexed attributes are not checked, candidates being already narrowed by indexedEntries
+func (l LSH) matchesFilters(e *LshEntry, q Query) bool {
+	for name, want := range q.Attributes {
+		if _, indexed := l.attributes[name]; indexed {
+			continue
+		}
+
+		attributed, ok := e.Value.(Attributed)
+		if !ok {
+			return false
+		}
+		if got, ok := attributed.GetAttribute(name); !ok || got != want {
+			return false
+		}
+	}
+
+	if q.Filter != nil && !q.Filter(e.Value) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,109 @@
+package lsh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCompany struct {
+	id      int
+	country string
+	active  bool
+}
+
+func (c testCompany) GetID() any {
+	return c.id
+}
+
+func (c testCompany) GetAttribute(name string) (string, bool) {
+	if name == "country" {
+		return c.country, true
+	}
+	return "", false
+}
+
+func givenTestCompanies() []KeyValue {
+	companies := []struct {
+		name    string
+		country string
+		active  bool
+	}{
+		{"acme corporation", "USA", true},
+		{"acme corporation", "CAN", true},
+		{"acme corporation", "CAN", false},
+		{"acme corporations", "FRA", true},
+		{"globex corporation", "MEX", true},
+	}
+	data := make([]KeyValue, len(companies))
+	for i, c := range companies {
+		data[i] = KeyValue{Key: c.name, Value: testCompany{id: i, country: c.country, active: c.active}}
+	}
+	return data
+}
+
+func TestSearchWithFilters(t *testing.T) {
+	index := BuildLSH(20, 10, 2, givenTestCompanies())
+
+	assertCanadianOnly := func(t *testing.T, results []LSHResult) {
+		assert.Equal(t, map[any]bool{1: true, 2: true}, resultIDs(results))
+	}
+
+	t.Run("attribute filter without index", func(t *testing.T) {
+		results := index.Search(Query{Key: "acme corporation", HashSimilarity: 0.9, Attributes: map[string]string{"country": "CAN"}})
+		assertCanadianOnly(t, results)
+	})
+
+	t.Run("attribute filter with index", func(t *testing.T) {
+		indexed := index
+		indexed.IndexAttributes("country")
+		results := indexed.Search(Query{Key: "acme corporation", HashSimilarity: 0.9, Attributes: map[string]string{"country": "CAN"}})
+		assertCanadianOnly(t, results)
+
+		results = indexed.Search(Query{Key: "acme corporation", HashSimilarity: 0.9, Attributes: map[string]string{"country": "BRA"}})
+		assert.Empty(t, results)
+	})
+
+	t.Run("attribute index narrows the candidates", func(t *testing.T) {
+		indexed := index
+		indexed.IndexAttributes("country")
+
+		entries, narrowed := indexed.indexedEntries(Query{Attributes: map[string]string{"country": "CAN"}})
+		assert.True(t, narrowed)
+		ids := map[any]bool{}
+		for e := range entries {
+			ids[e.Value.GetID()] = true
+		}
+		assert.Equal(t, map[any]bool{1: true, 2: true}, ids)
+
+		entries, narrowed = indexed.indexedEntries(Query{Attributes: map[string]string{"country": "CAN", "status": "active"}})
+		assert.True(t, narrowed)
+		assert.Len(t, entries, 2) // status is not indexed, it is checked on the candidates
+
+		_, narrowed = index.indexedEntries(Query{Attributes: map[string]string{"country": "CAN"}})
+		assert.False(t, narrowed)
+	})
+
+	t.Run("unknown attribute filters everything", func(t *testing.T) {
+		results := index.Search(Query{Key: "acme corporation", Attributes: map[string]string{"status": "active"}})
+		assert.Empty(t, results)
+	})
+
+	t.Run("predicate filter is applied before limit", func(t *testing.T) {
+		results := index.Search(Query{
+			Key:            "acme corporation",
+			HashSimilarity: 0.9,
+			Attributes:     map[string]string{"country": "CAN"},
+			Filter:         func(v Value) bool { return !v.(testCompany).active },
+			Limit:          1,
+		})
+		assert.Equal(t, map[any]bool{2: true}, resultIDs(results))
+	})
+
+	t.Run("limit keeps the best scores", func(t *testing.T) {
+		all := index.Search(Query{Key: "acme corporation"})
+		limited := index.Search(Query{Key: "acme corporation", Limit: 2})
+		assert.Len(t, limited, 2)
+		assert.Equal(t, all[1].Score, limited[1].Score)
+	})
+}
@@ -14,12 +14,16 @@ package lsh
 // https://www.pinecone.io/learn/series/faiss/locality-sensitive-hashing/
 
 import (
+	"cmp"
 	"fmt"
 	"gowtools/algo"
 	"math"
 	"math/rand/v2"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -44,6 +48,19 @@ type LSH struct {
 	signatureLength   int
 	nbBands           int
 	shingleWindowSize int
+
+	minHash  *minHasher // set when the index was built from a stream, replaces Vocab and HashFuncs
+	tokenize Tokenizer  // optional, see BuildLSHTokenized
+
+	attributes attributeIndex // see IndexAttributes
+}
+
+// signature returns the MinHash signature of the shingles, with the hash functions the index was built with
+func (l LSH) signature(shingles map[string]uint8) []hashVal {
+	if l.minHash != nil {
+		return l.minHash.signature(shingles)
+	}
+	return getHashSignature(shingles, l.signatureLength, l.HashFuncs, l.Vocab)
 }
 
 func isEqual(a []hashVal, b []hashVal) bool {
@@ -75,6 +92,10 @@ type KeyValue struct {
 
 type LSHBucket struct {
 	Bands map[string]LSHBucketBand
+
+	// keys holds the band hashes of Bands sorted lexicographically. Since a band hash is built value by value,
+	// every band sharing a prefix with another is contiguous in keys, which allows prefix (forest) probing
+	keys []string
 }
 
 type LSHBucketBand struct {
@@ -92,12 +113,7 @@ type LSHBucketBand struct {
 //
 // data is the data which defines the key to hash along its value (or pointer value preferable) to store in indexes
 func BuildLSH(signatureLength int, nBands int, shingleWindowSize int, data []KeyValue) LSH {
-	if signatureLength%nBands != 0 {
-		panic("lsh: signature length must be divisible by nb of bands")
-	}
-	if nBands < 1 {
-		panic("lsh: nBand must be at least 1")
-	}
+	checkBuildParams(signatureLength, nBands)
 
 	start := time.Now()
 
@@ -137,32 +153,66 @@ func BuildLSH(signatureLength int, nBands int, shingleWindowSize int, data []Key
 	log(fmt.Sprintf("vocab size is %d", len(vocabSlc)))
 
 	// prepare the hash functions
-	// Each hash function is ran based on the signature / hash length, with a randomized slice of vocab positions
+	// Each signature position has its own hash function, a randomized slice of vocab positions, so that the values of a band
+	// are independent and a shorter band prefix matches more buckets (see FindMultiProbe)
 	// See other comment in function below for more explanations
 
-	hashFuncs := make([][]hashVal, nBands)
+	hashFuncs := make([][]hashVal, signatureLength)
 	for i := range hashFuncs {
 		hashFuncs[i] = getNewHashVectorRandomized(vocabSlc)
 	}
 	log(fmt.Sprintf("Prepared %d random hash funcs for signature length of %d", len(hashFuncs), signatureLength))
 
+	log(fmt.Sprintf("Hashing %d elements... This can take some time", len(entries)))
+	for i := range entries {
+		e := &entries[i]
+		e.Singature = getHashSignature(e.Shingles, signatureLength, hashFuncs, vocabSlc)
+		e.Shingles = nil // shignles not needed anymore, free some memory
+	}
+
+	buckets := fillBuckets(entries, nBands)
+
+	log(fmt.Sprintf("loaded lsh index in %s", time.Since(start).String()))
+
+	return LSH{
+		Vocab:             vocabSlc,
+		HashFuncs:         hashFuncs,
+		Entries:           entries,
+		Buckets:           buckets,
+		signatureLength:   signatureLength,
+		nbBands:           nBands,
+		shingleWindowSize: shingleWindowSize,
+	}
+}
+
+func checkBuildParams(signatureLength int, nBands int) {
+	if nBands < 1 {
+		panic("lsh: nBand must be at least 1")
+	}
+	if signatureLength%nBands != 0 {
+		panic("lsh: signature length must be divisible by nb of bands")
+	}
+}
+
+// fillBuckets assigns each entry to its band buckets. Signatures must be set on the entries.
+// Buckets hold pointers to the entries, so the entries slice must not be reallocated afterwards
+func fillBuckets(entries []LshEntry, nBands int) []LSHBucket {
 	// prepare band buckets
 	// Each band bucket is to increase search speed and allow not having to iterate
 	// and compare all the data against an input, which can be expensive
 	// An input will be hashed on search, and we will try to only look into each bucket if there are entries to compare (candidates)
 	// This is the "locality" part of the algorithm
 	buckets := make([]LSHBucket, nBands)
+	for i := range buckets {
+		buckets[i].Bands = map[string]LSHBucketBand{}
+	}
 
-	log(fmt.Sprintf("Hashing %d elements... This can take some time", len(entries)))
 	for i := range entries {
 		e := &entries[i]
-		signature := getHashSignature(e.Shingles, signatureLength, hashFuncs, vocabSlc)
-		e.Shingles = nil // shignles not needed anymore, free some memory
-		e.Singature = signature
 
-		// Lastly, we create subvectors (nbBands)
+		// we create subvectors (nbBands)
 		// And assign it to the right bucket for increased search speed
-		bands := splitHashSignatureIntoSubvectors(nBands, signature)
+		bands := splitHashSignatureIntoSubvectors(nBands, e.Singature)
 		if len(bands) != len(buckets) {
 			panic("[lsh] signature nb of bands does not match nb of buckets allocated")
 		}
@@ -170,29 +220,25 @@ func BuildLSH(signatureLength int, nBands int, shingleWindowSize int, data []Key
 			bandHash := hashBandForBucketAccess(bands[i])
 			// Check if the band already exists. If so append
 			// If not, create it
-
-			if buckets[i].Bands == nil {
-				buckets[i].Bands = map[string]LSHBucketBand{
-					bandHash: {
-						Band:     bands[i],
-						Elements: []*LshEntry{e},
-					},
-				}
-			}
-
 			bucketBand, bandExistsInBucket := buckets[i].Bands[bandHash]
-			if bandExistsInBucket {
-				bucketBand.Elements = append(bucketBand.Elements, e)
-				buckets[i].Bands[bandHash] = bucketBand
-			} else {
-				buckets[i].Bands[bandHash] = LSHBucketBand{
-					Band:     bands[i],
-					Elements: []*LshEntry{e},
-				}
-
+			if !bandExistsInBucket {
+				bucketBand.Band = bands[i]
 			}
+			bucketBand.Elements = append(bucketBand.Elements, e)
+			buckets[i].Bands[bandHash] = bucketBand
+		}
+	}
+
+	// sort the band hashes of each bucket, used by prefix probing on search
+	for i := range buckets {
+		keys := make([]string, 0, len(buckets[i].Bands))
+		for bandHash := range buckets[i].Bands {
+			keys = append(keys, bandHash)
 		}
+		sort.Strings(keys)
+		buckets[i].keys = keys
 	}
+
 	if Verbose {
 		totalBucketElements := 0
 		for i := range buckets {
@@ -202,17 +248,7 @@ func BuildLSH(signatureLength int, nBands int, shingleWindowSize int, data []Key
 		log(fmt.Sprintf("made %d buckets of avg size %d", len(buckets), avgBucketSize))
 	}
 
-	log(fmt.Sprintf("loaded lsh index in %s", time.Since(start).String()))
-
-	return LSH{
-		Vocab:             vocabSlc,
-		HashFuncs:         hashFuncs,
-		Entries:           entries,
-		Buckets:           buckets,
-		signatureLength:   signatureLength,
-		nbBands:           nBands,
-		shingleWindowSize: shingleWindowSize,
-	}
+	return buckets
 }
 
 func hashBandForBucketAccess(band []hashVal) string {
@@ -262,11 +298,13 @@ func getNewHashVectorRandomized(vocabSlc []string) []hashVal {
 	return shuffledHashValues
 }
 
+// getHashSignature computes the MinHash signature of the shingles. hashFuncs has one hash function per signature position,
+// or one per band for indexes built before, whose band values are then all the same
 func getHashSignature(entryShingles map[string]uint8, signatureLength int, hashFuncs [][]hashVal, vocab []string) []hashVal {
 	if signatureLength%len(hashFuncs) != 0 {
 		panic("signature length and nBands (hashfuncs) must be divisable")
 	}
-	bandLength := signatureLength / len(hashFuncs)
+	positionsPerHashFunc := signatureLength / len(hashFuncs)
 
 	// once we have all shingle, we create a sparse (wide/long) vectors
 	// which is filled with 0 and set to 1 when a shingling is present in the global vocab
@@ -282,20 +320,16 @@ func getHashSignature(entryShingles map[string]uint8, signatureLength int, hashF
 	// Second, we iterate each value (some random vocab shignling) of the randomized hash vector, check if the current vector has it, if not loop until we hit a random value that is in our entry's shingles
 	// We repeat this n amount of times (signatureLength) to build a minhash signature, aka our dense vector
 	signature := make([]hashVal, signatureLength)
-	idxHashFunc := 0
-	for i := 0; i < signatureLength; i += bandLength {
-		hashFunc := hashFuncs[idxHashFunc]
-		for j := range bandLength {
-			// find the first matching element in hashFunc values (which are random positions, 1-indexed)
-			for _, randomPosHashVal := range hashFunc {
-				isValueInVector := sparseVector[randomPosHashVal-1]
-				if isValueInVector == 1 {
-					signature[i+j] = randomPosHashVal // add the 1-indexed value, not 0 indexed
-					break
-				}
+	for i := range signature {
+		hashFunc := hashFuncs[i/positionsPerHashFunc]
+		// find the first matching element in hashFunc values (which are random positions, 1-indexed)
+		for _, randomPosHashVal := range hashFunc {
+			isValueInVector := sparseVector[randomPosHashVal-1]
+			if isValueInVector == 1 {
+				signature[i] = randomPosHashVal // add the 1-indexed value, not 0 indexed
+				break
 			}
 		}
-		idxHashFunc += 1
 	}
 
 	return signature
@@ -314,24 +348,72 @@ func splitHashSignatureIntoSubvectors(nbBands int, fullSignature []hashVal) [][]
 	return bands
 }
 
-type LSHResult struct {
-	Score float64
-	Value any
+// LSHResult is the same as algo.Result, so LSH results can be compared with the exact indexes of algo (ground truth)
+type LSHResult = algo.Result
+
+// Query describes a search in the index. Only Key is required
+type Query struct {
+	Key string
+
+	// HashSimilarity is the minimum signature similarity (0 to 1) a candidate must have to be returned
+	HashSimilarity float64
+
+	// ProbeDepth is the number of trailing band values that may differ from key's bands (see FindMultiProbe)
+	ProbeDepth int
+
+	// Attributes keeps only the candidates whose value has all of these exact attribute values.
+	// Values must implement Attributed. Attributes indexed with IndexAttributes are resolved with a lookup
+	Attributes map[string]string
+
+	// Filter is an optional predicate a candidate value must satisfy. It is evaluated before scoring
+	Filter func(v Value) bool
+
+	// Rerank optionally scores the candidates that passed HashSimilarity by comparing the query key with their original key,
+	// for example with algo.JaroWinkler or algo.LevenshteinSimilarity. Results are then sorted and limited on that score
+	Rerank func(query string, key string) float64
+
+	// Limit is the maximum number of results to return, 0 meaning no limit
+	Limit int
 }
 
+// Find returns the indexed values whose signature has at least hashSimilarity with key's signature, best scores first.
+// Only entries sharing at least one exact band with key are considered as candidates
 func (l LSH) Find(key string, hashSimilarity float64) []LSHResult {
-	shingles := shingle(l.shingleWindowSize, key)
-	fmt.Printf("search shingles: %#v\n", shingles)
+	return l.Search(Query{Key: key, HashSimilarity: hashSimilarity})
+}
 
-	searchSignature := getHashSignature(shingles, l.signatureLength, l.HashFuncs, l.Vocab)
+// FindMultiProbe works like Find, but also probes the buckets whose band only shares a prefix with key's band.
+// probeDepth is the number of trailing band values that may differ (0 is the same as Find), in the spirit of an LSH Forest.
+// This raises recall with few bands, at the cost of comparing more candidates. The prefix is never shorter than one value
+func (l LSH) FindMultiProbe(key string, hashSimilarity float64, probeDepth int) []LSHResult {
+	return l.Search(Query{Key: key, HashSimilarity: hashSimilarity, ProbeDepth: probeDepth})
+}
+
+// Search runs q against the index. Candidates are filtered before being scored and the results are limited after sorting,
+// so a filtered search returns the best matching values among those that satisfy the filters
+func (l LSH) Search(q Query) []LSHResult {
+	if q.ProbeDepth < 0 {
+		panic("lsh: probeDepth cannot be negative")
+	}
 
-	searchSignatureMap := make(map[hashVal]uint8, len(searchSignature))
-	for _, hashVal := range searchSignature {
-		searchSignatureMap[hashVal] = 1
+	key := q.Key
+	if l.tokenize != nil {
+		key = l.tokenize(key)
 	}
 
+	shingles := shingle(l.shingleWindowSize, key)
+	log(fmt.Sprintf("search shingles: %#v", shingles))
+
+	searchSignature := l.signature(shingles)
+
 	searchBands := splitHashSignatureIntoSubvectors(l.nbBands, searchSignature)
 
+	// only the entries having the indexed attributes filtered on can be candidates
+	allowed, narrowed := l.indexedEntries(q)
+	if narrowed && len(allowed) == 0 {
+		return []LSHResult{}
+	}
+
 	// first, evaluate candidates by looking into buckets if we have a match
 	// to not have to compare against entire data set
 	candidatesDeduped := map[any]*LshEntry{}
@@ -339,37 +421,98 @@ func (l LSH) Find(key string, hashSimilarity float64) []LSHResult {
 	for i, searchBand := range searchBands {
 		bucket := l.Buckets[i]
 
-		searchBandHash := hashBandForBucketAccess(searchBand)
-
-		if bucketBand, existsInBucket := bucket.Bands[searchBandHash]; existsInBucket {
-			bucketMatchCount++
-			for j, elem := range bucketBand.Elements {
-				candidatesDeduped[elem.Value.GetID()] = bucketBand.Elements[j]
+		if q.ProbeDepth == 0 {
+			searchBandHash := hashBandForBucketAccess(searchBand)
+			if bucketBand, existsInBucket := bucket.Bands[searchBandHash]; existsInBucket {
+				bucketMatchCount++
+				addCandidates(candidatesDeduped, bucketBand, allowed)
 			}
+			continue
 		}
 
+		prefixLen := len(searchBand) - q.ProbeDepth
+		if prefixLen < 1 {
+			prefixLen = 1
+		}
+		prefix := hashBandForBucketAccess(searchBand[:prefixLen])
+		for j := sort.SearchStrings(bucket.keys, prefix); j < len(bucket.keys) && strings.HasPrefix(bucket.keys[j], prefix); j++ {
+			bucketMatchCount++
+			addCandidates(candidatesDeduped, bucket.Bands[bucket.keys[j]], allowed)
+		}
 	}
 	log(fmt.Sprintf("Found %d candidates in %d buckets. Comparing", len(candidatesDeduped), bucketMatchCount))
 
-	// then, check vector similarity for each entry
-	results := []LSHResult{}
+	// then, drop the candidates that do not satisfy the filters
+	// and lay the signatures of the others in a contiguous matrix to check vector similarity in a single batch
+	candidates := make([]*LshEntry, 0, len(candidatesDeduped))
 	for _, c := range candidatesDeduped {
-		similarity := algo.CosineSimilarityUint32(c.Singature, searchSignature)
-		if similarity >= hashSimilarity {
+		if l.matchesFilters(c, q) {
+			candidates = append(candidates, c)
+		}
+	}
+	filtered := len(candidatesDeduped) - len(candidates)
+
+	results := []LSHResult{}
+	buf := searchBuffers.Get().(*searchBuffer)
+	defer searchBuffers.Put(buf)
+	matrix := buf.matrix[:0]
+	for _, c := range candidates {
+		matrix = append(matrix, c.Singature...)
+	}
+	buf.matrix = matrix
+	similarities := slices.Grow(buf.similarities[:0], len(candidates))[:len(candidates)]
+	buf.similarities = similarities
+	if err := algo.CosineBatchUint32(searchSignature, matrix, similarities); err != nil {
+		// an empty search signature (key shorter than the shingle window) cannot be compared
+		log("search signature cannot be compared: " + err.Error())
+		similarities = nil
+	}
+
+	for i, similarity := range similarities {
+		// NaN (empty candidate signatures) never passes
+		if similarity >= q.HashSimilarity {
+			c := candidates[i]
+			if q.Rerank != nil {
+				similarity = q.Rerank(q.Key, c.OriginalKey)
+			}
 			valueCpy := c.Value
 			results = append(results, LSHResult{Score: similarity, Value: valueCpy})
 		}
 	}
-	log(fmt.Sprintf("Found %d results with good hash similarity, pruned %d", len(results), len(candidatesDeduped)-len(results)))
+	log(fmt.Sprintf("Found %d results with good hash similarity, filtered %d, pruned %d", len(results), filtered, len(candidatesDeduped)-filtered-len(results)))
 
 	// order the results by score
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
+	slices.SortFunc(results, func(a, b LSHResult) int {
+		return cmp.Compare(b.Score, a.Score)
 	})
 
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+
 	return results
 }
 
+// searchBuffer holds the candidate signatures matrix and their similarities, reused across searches to not allocate them each time
+type searchBuffer struct {
+	matrix       []hashVal
+	similarities []float64
+}
+
+var searchBuffers = sync.Pool{New: func() any { return &searchBuffer{} }}
+
+// addCandidates adds the elements of the band to candidates, only those in allowed unless it is nil
+func addCandidates(candidates map[any]*LshEntry, bucketBand LSHBucketBand, allowed map[*LshEntry]struct{}) {
+	for j, elem := range bucketBand.Elements {
+		if allowed != nil {
+			if _, ok := allowed[elem]; !ok {
+				continue
+			}
+		}
+		candidates[elem.Value.GetID()] = bucketBand.Elements[j]
+	}
+}
+
 type LSHSearchResult struct {
 	Score float64
 	Val   any
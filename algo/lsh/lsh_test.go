@@ -0,0 +1,190 @@
+package lsh
+
+import (
+	"gowtools/algo"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testValue struct {
+	id   int
+	name string
+}
+
+func (v testValue) GetID() any {
+	return v.id
+}
+
+func givenTestData() []KeyValue {
+	names := []string{
+		"william brisson",
+		"william brison",
+		"willam brisson",
+		"bill brisson",
+		"john doe",
+		"jane doe",
+		"johnny dough",
+		"acme corporation",
+		"acme corp",
+		"globex corporation",
+	}
+	data := make([]KeyValue, len(names))
+	for i, n := range names {
+		data[i] = KeyValue{Key: n, Value: testValue{id: i, name: n}}
+	}
+	return data
+}
+
+func resultIDs(results []LSHResult) map[any]bool {
+	ids := map[any]bool{}
+	for _, r := range results {
+		ids[r.Value.(Value).GetID()] = true
+	}
+	return ids
+}
+
+// givenNoisyNames returns the test data along variants of each name missing one character
+func givenNoisyNames() []KeyValue {
+	data := givenTestData()
+	for _, d := range givenTestData() {
+		for i := 1; i < len(d.Key); i += 3 {
+			key := d.Key[:i] + d.Key[i+1:]
+			data = append(data, KeyValue{Key: key, Value: testValue{id: len(data), name: key}})
+		}
+	}
+	return data
+}
+
+// trueNeighbours returns the IDs of the values whose key shingles have at least minJaccard similarity with query's, by brute force
+func trueNeighbours(query string, data []KeyValue, shingleWindowSize int, minJaccard float64) map[any]bool {
+	queryShingles := algo.NewSet(slices.Collect(maps.Keys(shingle(shingleWindowSize, query)))...)
+	ids := map[any]bool{}
+	for _, d := range data {
+		keyShingles := algo.NewSet(slices.Collect(maps.Keys(shingle(shingleWindowSize, d.Key)))...)
+		if algo.JaccardSet(queryShingles, keyShingles) >= minJaccard {
+			ids[d.Value.GetID()] = true
+		}
+	}
+	return ids
+}
+
+func TestFind(t *testing.T) {
+	index := BuildLSH(20, 10, 2, givenTestData())
+
+	results := index.Find("william brisson", 0.5)
+	assert.NotEmpty(t, results)
+	assert.True(t, resultIDs(results)[0])
+	assert.InDelta(t, 1.0, results[0].Score, 1e-9)
+
+	for i := 1; i < len(results); i++ {
+		assert.GreaterOrEqual(t, results[i-1].Score, results[i].Score)
+	}
+}
+
+func TestFindMultiProbe(t *testing.T) {
+	// few bands with long band lengths, so exact band collisions are rare
+	index := BuildLSH(32, 2, 2, givenTestData())
+	queries := []string{"william brisson", "wiliam brison", "jon doe", "acme corporations"}
+
+	for _, q := range queries {
+		exact := resultIDs(index.Find(q, 0))
+
+		t.Run("probe depth 0 is the same as Find for "+q, func(t *testing.T) {
+			assert.Equal(t, exact, resultIDs(index.FindMultiProbe(q, 0, 0)))
+		})
+
+		t.Run("recall never decreases with probe depth for "+q, func(t *testing.T) {
+			previous := exact
+			for depth := 1; depth <= 16; depth++ {
+				probed := resultIDs(index.FindMultiProbe(q, 0, depth))
+				for id := range previous {
+					assert.True(t, probed[id], "depth %d lost result %v", depth, id)
+				}
+				previous = probed
+			}
+		})
+	}
+
+	t.Run("probing finds more true neighbours than exact bands", func(t *testing.T) {
+		data := givenNoisyNames()
+		for name, index := range map[string]LSH{
+			"vocab":  BuildLSH(32, 2, 2, data),
+			"stream": BuildLSHStream(32, 2, 2, slices.Values(data)),
+		} {
+			exactFound, probedFound, neighbours := 0, 0, 0
+			for _, q := range queries {
+				truth := trueNeighbours(q, data, 2, 0.5)
+				neighbours += len(truth)
+				exact := resultIDs(index.Find(q, 0))
+				probed := resultIDs(index.FindMultiProbe(q, 0, 15))
+				for id := range truth {
+					if exact[id] {
+						exactFound++
+					}
+					if probed[id] {
+						probedFound++
+					}
+				}
+			}
+			assert.Greater(t, probedFound, exactFound, name)
+			assert.LessOrEqual(t, probedFound, neighbours, name)
+		}
+	})
+
+	t.Run("panics on negative probe depth", func(t *testing.T) {
+		assert.Panics(t, func() { index.FindMultiProbe("john doe", 0, -1) })
+	})
+}
+
+func TestSearchWithRerank(t *testing.T) {
+	index := BuildLSH(20, 10, 2, givenTestData())
+
+	results := index.Search(Query{Key: "william brisson", Rerank: algo.JaroWinkler})
+	assert.NotEmpty(t, results)
+	for _, r := range results {
+		name := r.Value.(testValue).name
+		assert.Equal(t, algo.JaroWinkler("william brisson", name), r.Score)
+	}
+	assert.Equal(t, 0, results[0].Value.(testValue).id)
+}
+
+// givenBenchData returns every combination of a few first and last names
+func givenBenchData() []KeyValue {
+	first := []string{"william", "bill", "john", "johnny", "jane", "janet", "mary", "marie", "robert", "bob",
+		"james", "jim", "linda", "lynda", "michael", "mike", "sarah", "sara", "thomas", "tom"}
+	last := []string{"brisson", "brison", "doe", "dough", "smith", "smyth", "johnson", "jonson", "brown", "braun",
+		"miller", "muller", "davis", "davies", "wilson", "willson", "moore", "more", "taylor", "tailor"}
+	data := make([]KeyValue, 0, len(first)*len(last)*5)
+	for i := range 5 {
+		for _, f := range first {
+			for _, l := range last {
+				key := f + " " + l + strings.Repeat(" jr", i)
+				data = append(data, KeyValue{Key: key, Value: testValue{id: len(data), name: key}})
+			}
+		}
+	}
+	return data
+}
+
+func BenchmarkFind(b *testing.B) {
+	index := BuildLSH(64, 16, 2, givenBenchData())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		index.Find("wiliam brison", 0.5)
+	}
+}
+
+func BenchmarkFindMultiProbe(b *testing.B) {
+	// probing down to the first value of the bands, most of the entries are candidates to rerank
+	index := BuildLSH(64, 4, 2, givenBenchData())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		index.FindMultiProbe("wiliam brison", 0.5, 15)
+	}
+}
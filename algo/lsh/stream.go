@@ -0,0 +1,119 @@
+package lsh
+
+import (
+	"fmt"
+	"gowtools/algo"
+	"iter"
+	"math/rand/v2"
+	"time"
+)
+
+// BuildLSHStream builds an index from a sequence of key values, such as a database cursor or a file scan.
+// Unlike BuildLSH, neither the raw dataset nor the global vocabulary are held in memory: each key is shingled and hashed
+// as soon as it is read, and only its signature is kept along its value.
+//
+// Since there is no vocabulary to permute, signatures are MinHashes computed with random universal hash functions over the shingles.
+// Vocab and HashFuncs of the returned index are nil.
+//
+// The parameters have the same meaning and constraints as BuildLSH
+func BuildLSHStream(signatureLength int, nBands int, shingleWindowSize int, data iter.Seq[KeyValue]) LSH {
+	return buildLSHStream(signatureLength, nBands, shingleWindowSize, nil, data)
+}
+
+// BuildLSHTokenized builds an index like BuildLSHStream, but shingles tokenize(key) instead of the raw key.
+// Searches on the returned index tokenize the query key the same way, while Rerank still compares the raw keys
+func BuildLSHTokenized(signatureLength int, nBands int, shingleWindowSize int, tokenize Tokenizer, data iter.Seq[KeyValue]) LSH {
+	return buildLSHStream(signatureLength, nBands, shingleWindowSize, tokenize, data)
+}
+
+func buildLSHStream(signatureLength int, nBands int, shingleWindowSize int, tokenize Tokenizer, data iter.Seq[KeyValue]) LSH {
+	checkBuildParams(signatureLength, nBands)
+
+	start := time.Now()
+
+	minHash := newMinHasher(signatureLength)
+
+	entries := []LshEntry{}
+	for d := range data {
+		key := d.Key
+		if tokenize != nil {
+			key = tokenize(key)
+		}
+		entries = append(entries, LshEntry{
+			OriginalKey: d.Key,
+			Singature:   minHash.signature(shingle(shingleWindowSize, key)),
+			Value:       d.Value,
+		})
+	}
+	log(fmt.Sprintf("Hashed %d streamed elements", len(entries)))
+
+	// entries won't grow anymore, buckets can safely point to them
+	buckets := fillBuckets(entries, nBands)
+
+	log(fmt.Sprintf("loaded lsh index in %s", time.Since(start).String()))
+
+	return LSH{
+		Entries:           entries,
+		Buckets:           buckets,
+		signatureLength:   signatureLength,
+		nbBands:           nBands,
+		shingleWindowSize: shingleWindowSize,
+		minHash:           minHash,
+		tokenize:          tokenize,
+	}
+}
+
+// BuildLSHFromChan builds an index like BuildLSHStream, reading key values from data until it is closed
+func BuildLSHFromChan(signatureLength int, nBands int, shingleWindowSize int, data <-chan KeyValue) LSH {
+	return BuildLSHStream(signatureLength, nBands, shingleWindowSize, func(yield func(KeyValue) bool) {
+		for d := range data {
+			if !yield(d) {
+				return
+			}
+		}
+	})
+}
+
+// minHasher computes MinHash signatures without a vocabulary.
+// Each shingle is hashed once, then each signature position applies its own multiply-shift universal hash (a*x + b) to it,
+// keeping the minimum over all the shingles
+type minHasher struct {
+	a []uint64 // odd multipliers
+	b []uint64
+}
+
+func newMinHasher(signatureLength int) *minHasher {
+	h := &minHasher{
+		a: make([]uint64, signatureLength),
+		b: make([]uint64, signatureLength),
+	}
+	for i := range signatureLength {
+		h.a[i] = rand.Uint64() | 1
+		h.b[i] = rand.Uint64()
+	}
+	return h
+}
+
+func (h *minHasher) signature(shingles map[string]uint8) []hashVal {
+	signature := make([]hashVal, len(h.a))
+	if len(shingles) == 0 {
+		return signature
+	}
+
+	for i := range signature {
+		signature[i] = maxHashVal
+	}
+
+	for s := range shingles {
+		x := algo.HashString64(s, 0)
+		for i := range signature {
+			// keep the high bits, they are the best mixed ones of a multiply-shift hash
+			v := hashVal((h.a[i]*x + h.b[i]) >> 32)
+			if v < signature[i] {
+				signature[i] = v
+			}
+		}
+	}
+
+	return signature
+}
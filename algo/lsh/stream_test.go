@@ -0,0 +1,71 @@
+package lsh
+
+import (
+	"gowtools/algo"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildLSHStream(t *testing.T) {
+	data := givenTestData()
+	index := BuildLSHStream(20, 10, 2, slices.Values(data))
+
+	assert.Nil(t, index.Vocab)
+	assert.Nil(t, index.HashFuncs)
+	assert.Len(t, index.Entries, len(data))
+
+	results := index.Find("william brisson", 0.5)
+	assert.NotEmpty(t, results)
+	assert.True(t, resultIDs(results)[0])
+	assert.InDelta(t, 1.0, results[0].Score, 1e-9)
+
+	t.Run("unrelated keys are not found", func(t *testing.T) {
+		results := index.Find("zzzzzz", 0)
+		assert.Empty(t, results)
+	})
+
+	t.Run("keys shorter than the shingle window have an empty signature", func(t *testing.T) {
+		index := BuildLSHStream(4, 2, 5, slices.Values([]KeyValue{{Key: "abc", Value: testValue{id: 1}}}))
+		assert.Equal(t, []hashVal{0, 0, 0, 0}, index.Entries[0].Singature)
+	})
+}
+
+func TestBuildLSHFromChan(t *testing.T) {
+	data := givenTestData()
+	ch := make(chan KeyValue)
+	go func() {
+		for _, d := range data {
+			ch <- d
+		}
+		close(ch)
+	}()
+
+	index := BuildLSHFromChan(20, 10, 2, ch)
+	assert.Len(t, index.Entries, len(data))
+
+	results := index.Find("acme corporation", 0.5)
+	assert.True(t, resultIDs(results)[7])
+}
+
+func TestBuildLSHTokenized(t *testing.T) {
+	data := []KeyValue{
+		{Key: "Catherine Smith", Value: testValue{id: 0}},
+		{Key: "Kathryn Smyth", Value: testValue{id: 1}},
+		{Key: "Robert Jones", Value: testValue{id: 2}},
+	}
+	index := BuildLSHTokenized(20, 10, 2, PhoneticTokenizer(algo.DoubleMetaphoneEncoder), slices.Values(data))
+
+	// the phonetic keys are equal, so are the signatures
+	results := index.Find("Cathrine Smithe", 0.99)
+	assert.Equal(t, map[any]bool{0: true, 1: true}, resultIDs(results))
+
+	t.Run("original keys are kept for reranking", func(t *testing.T) {
+		assert.Equal(t, "Kathryn Smyth", index.Entries[1].OriginalKey)
+
+		results := index.Search(Query{Key: "Kathryn Smyth", HashSimilarity: 0.99, Rerank: algo.LevenshteinSimilarity})
+		assert.Equal(t, 1, results[0].Value.(testValue).id)
+		assert.Equal(t, 1.0, results[0].Score)
+	})
+}
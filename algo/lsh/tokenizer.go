@@ -0,0 +1,14 @@
+package lsh
+
+import "gowtools/algo"
+
+// Tokenizer transforms a key before it gets shingled, both when indexing and when searching
+type Tokenizer func(key string) string
+
+// PhoneticTokenizer indexes keys by the sound of their words (see algo.PhoneticKey),
+// so that names spelled differently but pronounced alike share their shingles
+func PhoneticTokenizer(enc algo.PhoneticEncoder) Tokenizer {
+	return func(key string) string {
+		return algo.PhoneticKey(enc, key)
+	}
+}
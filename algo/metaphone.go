@@ -0,0 +1,155 @@
+package algo
+
+// Metaphone returns the original (Lawrence Philips, 1990) Metaphone code of s, without any length limit.
+// 0 (zero) stands for the TH sound and X for SH. Non ascii letters are ignored, an empty string is returned when s has no letter
+func Metaphone(s string) string {
+	w := upperLetters(s)
+	if len(w) == 0 {
+		return ""
+	}
+
+	// at returns the letter at i, or 0 when out of bounds, which simplifies looking around
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+		return w[i]
+	}
+	isFront := func(c byte) bool {
+		return c == 'E' || c == 'I' || c == 'Y'
+	}
+	last := len(w) - 1
+
+	code := make([]byte, 0, len(w))
+
+	// initial letters exceptions
+	start := 0
+	switch {
+	case w[0] == 'A' && at(1) == 'E':
+		code = append(code, 'E')
+		start = 2
+	case (w[0] == 'G' || w[0] == 'K' || w[0] == 'P') && at(1) == 'N':
+		code = append(code, 'N')
+		start = 2
+	case w[0] == 'W' && at(1) == 'R':
+		code = append(code, 'R')
+		start = 2
+	case w[0] == 'W' && at(1) == 'H':
+		code = append(code, 'W')
+		start = 2
+	case w[0] == 'X':
+		code = append(code, 'S')
+		start = 1
+	}
+
+	for n := start; n < len(w); n++ {
+		c := w[n]
+
+		// double letters are coded once, except C
+		if c == at(n-1) && c != 'C' {
+			continue
+		}
+
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			// vowels are only kept when they start the word
+			if n == 0 {
+				code = append(code, c)
+			}
+		case 'B':
+			// silent in a final MB, like dumb
+			if !(at(n-1) == 'M' && n == last) {
+				code = append(code, 'B')
+			}
+		case 'C':
+			switch {
+			case at(n-1) == 'S' && isFront(at(n+1)):
+				// silent in SCI, SCE and SCY
+			case at(n+1) == 'I' && at(n+2) == 'A':
+				code = append(code, 'X')
+			case isFront(at(n + 1)):
+				code = append(code, 'S')
+			case at(n-1) == 'S' && at(n+1) == 'H':
+				code = append(code, 'K')
+			case at(n+1) == 'H':
+				if n == 0 && !isVowel(at(n+2)) {
+					// christ, chlorine
+					code = append(code, 'K')
+				} else {
+					code = append(code, 'X')
+				}
+			default:
+				code = append(code, 'K')
+			}
+		case 'D':
+			if at(n+1) == 'G' && isFront(at(n+2)) {
+				// DGE, DGI, DGY sound like J
+				code = append(code, 'J')
+				n += 2
+			} else {
+				code = append(code, 'T')
+			}
+		case 'G':
+			switch {
+			case at(n+1) == 'H' && !(n+1 == last || isVowel(at(n+2))):
+				// silent in GH not at the end nor before a vowel, like knight
+			case at(n+1) == 'N' && (n+1 == last || (at(n+2) == 'E' && at(n+3) == 'D' && n+3 == last)):
+				// silent in a final GN or GNED, like sign and signed
+			case isFront(at(n+1)) && at(n-1) != 'G':
+				code = append(code, 'J')
+			default:
+				code = append(code, 'K')
+			}
+		case 'H':
+			// silent at the end, after C, S, P, T, G (which already code it) or when no vowel follows
+			prev := at(n - 1)
+			if n != last && prev != 'C' && prev != 'S' && prev != 'P' && prev != 'T' && prev != 'G' && isVowel(at(n+1)) {
+				code = append(code, 'H')
+			}
+		case 'K':
+			if at(n-1) != 'C' {
+				code = append(code, 'K')
+			}
+		case 'P':
+			if at(n+1) == 'H' {
+				code = append(code, 'F')
+			} else {
+				code = append(code, 'P')
+			}
+		case 'Q':
+			code = append(code, 'K')
+		case 'S':
+			if at(n+1) == 'H' || (at(n+1) == 'I' && (at(n+2) == 'O' || at(n+2) == 'A')) {
+				code = append(code, 'X')
+			} else {
+				code = append(code, 'S')
+			}
+		case 'T':
+			switch {
+			case at(n+1) == 'I' && (at(n+2) == 'O' || at(n+2) == 'A'):
+				code = append(code, 'X')
+			case at(n+1) == 'C' && at(n+2) == 'H':
+				// silent in TCH, the CH codes it
+			case at(n+1) == 'H':
+				code = append(code, '0')
+			default:
+				code = append(code, 'T')
+			}
+		case 'V':
+			code = append(code, 'F')
+		case 'W', 'Y':
+			if isVowel(at(n + 1)) {
+				code = append(code, c)
+			}
+		case 'X':
+			code = append(code, 'K', 'S')
+		case 'Z':
+			code = append(code, 'S')
+		default:
+			// F, J, L, M, N and R are kept as is
+			code = append(code, c)
+		}
+	}
+
+	return string(code)
+}
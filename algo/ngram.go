@@ -0,0 +1,44 @@
+package algo
+
+import "unicode/utf8"
+
+// NGrams returns the distinct n runes long substrings of s. A non-empty string shorter than n is its own single n-gram.
+// The n-grams share s's memory, no string is copied
+func NGrams(s string, n int) Set[string] {
+	if n < 1 {
+		panic("algo: n-gram size must be at least 1")
+	}
+
+	grams := Set[string]{}
+	if s == "" {
+		return grams
+	}
+
+	// end is the byte offset right after the n-th rune following start
+	end := 0
+	for range n {
+		if end == len(s) {
+			grams[s] = struct{}{}
+			return grams
+		}
+		_, size := utf8.DecodeRuneInString(s[end:])
+		end += size
+	}
+
+	start := 0
+	for {
+		grams[s[start:end]] = struct{}{}
+		if end == len(s) {
+			return grams
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		start += size
+		_, size = utf8.DecodeRuneInString(s[end:])
+		end += size
+	}
+}
+
+// NGramSimilarity returns the Jaccard similarity of the n-grams (see NGrams) of a and b
+func NGramSimilarity(a string, b string, n int) float64 {
+	return JaccardSet(NGrams(a, n), NGrams(b, n))
+}
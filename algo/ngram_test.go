@@ -0,0 +1,19 @@
+package algo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNGrams(t *testing.T) {
+	assert.Equal(t, NewSet("ab", "bc", "cd"), NGrams("abcd", 2))
+	assert.Equal(t, NewSet("ab", "ba"), NGrams("ababab", 2)) // distinct values only
+	assert.Equal(t, NewSet("日本", "本語"), NGrams("日本語", 2))
+	assert.Equal(t, NewSet("ab"), NGrams("ab", 3))
+	assert.Empty(t, NGrams("", 2))
+	assert.Panics(t, func() { NGrams("abc", 0) })
+
+	assert.InDelta(t, 0.5, NGramSimilarity("abcd", "bcde", 2), 1e-9)
+	assert.InDelta(t, 1.0, NGramSimilarity("", "", 2), 1e-9)
+}
@@ -0,0 +1,97 @@
+package algo
+
+import "bytes"
+
+// nysiisMaxLen is the length the original NYSIIS algorithm truncates codes to
+const nysiisMaxLen = 6
+
+// NYSIIS returns the New York State Identification and Intelligence System code of s, 6 letters at most.
+// It is more accurate than Soundex on surnames. Non ascii letters are ignored, an empty string is returned when s has no letter
+func NYSIIS(s string) string {
+	w := upperLetters(s)
+	if len(w) == 0 {
+		return ""
+	}
+
+	// translate the first letters
+	switch {
+	case bytes.HasPrefix(w, []byte("MAC")):
+		copy(w, "MCC")
+	case bytes.HasPrefix(w, []byte("KN")):
+		copy(w, "NN")
+	case w[0] == 'K':
+		w[0] = 'C'
+	case bytes.HasPrefix(w, []byte("PH")), bytes.HasPrefix(w, []byte("PF")):
+		copy(w, "FF")
+	case bytes.HasPrefix(w, []byte("SCH")):
+		copy(w, "SSS")
+	}
+
+	// translate the last letters
+	if n := len(w); n >= 2 {
+		switch string(w[n-2:]) {
+		case "EE", "IE":
+			w = append(w[:n-2], 'Y')
+		case "DT", "RT", "RD", "NT", "ND":
+			w = append(w[:n-2], 'D')
+		}
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+		return w[i]
+	}
+
+	key := []byte{w[0]}
+	for i := 1; i < len(w); i++ {
+		// each rule rewrites w in place, so the following letters see the translated ones
+		switch c := w[i]; {
+		case c == 'E' && at(i+1) == 'V':
+			w[i], w[i+1] = 'A', 'F'
+		case isVowel(c):
+			w[i] = 'A'
+		case c == 'Q':
+			w[i] = 'G'
+		case c == 'Z':
+			w[i] = 'S'
+		case c == 'M':
+			w[i] = 'N'
+		case c == 'K':
+			if at(i+1) == 'N' {
+				w[i] = 'N'
+			} else {
+				w[i] = 'C'
+			}
+		case c == 'S' && at(i+1) == 'C' && at(i+2) == 'H':
+			w[i+1], w[i+2] = 'S', 'S'
+		case c == 'P' && at(i+1) == 'H':
+			w[i], w[i+1] = 'F', 'F'
+		case c == 'H' && (!isVowel(w[i-1]) || !isVowel(at(i+1))):
+			w[i] = w[i-1]
+		case c == 'W' && isVowel(w[i-1]):
+			w[i] = w[i-1]
+		}
+
+		if w[i] != key[len(key)-1] {
+			key = append(key, w[i])
+		}
+	}
+
+	// clean the end of the key
+	if len(key) > 1 && key[len(key)-1] == 'S' {
+		key = key[:len(key)-1]
+	}
+	if len(key) > 2 && key[len(key)-2] == 'A' && key[len(key)-1] == 'Y' {
+		key = append(key[:len(key)-2], 'Y')
+	}
+	if len(key) > 1 && key[len(key)-1] == 'A' {
+		key = key[:len(key)-1]
+	}
+
+	if len(key) > nysiisMaxLen {
+		key = key[:nysiisMaxLen]
+	}
+	return string(key)
+}
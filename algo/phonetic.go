@@ -0,0 +1,54 @@
+package algo
+
+import "strings"
+
+// PhoneticEncoder turns a word into a key shared by the words that sound alike, mostly for english names
+type PhoneticEncoder interface {
+	Encode(s string) string
+}
+
+// PhoneticFunc adapts an encoding function to the PhoneticEncoder interface
+type PhoneticFunc func(s string) string
+
+func (f PhoneticFunc) Encode(s string) string {
+	return f(s)
+}
+
+var (
+	SoundexEncoder         PhoneticEncoder = PhoneticFunc(Soundex)
+	MetaphoneEncoder       PhoneticEncoder = PhoneticFunc(Metaphone)
+	DoubleMetaphoneEncoder PhoneticEncoder = PhoneticFunc(DoubleMetaphonePrimary)
+	NYSIISEncoder          PhoneticEncoder = PhoneticFunc(NYSIIS)
+)
+
+// PhoneticKey encodes each space separated word of s and joins the codes with a space.
+// Words without any code (no letters) are dropped
+func PhoneticKey(enc PhoneticEncoder, s string) string {
+	words := strings.Fields(s)
+	codes := make([]string, 0, len(words))
+	for _, w := range words {
+		if code := enc.Encode(w); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return strings.Join(codes, " ")
+}
+
+// upperLetters keeps the ascii letters of s, upper cased
+func upperLetters(s string) []byte {
+	letters := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c >= 'A' && c <= 'Z' {
+			letters = append(letters, c)
+		}
+	}
+	return letters
+}
+
+func isVowel(c byte) bool {
+	return c == 'A' || c == 'E' || c == 'I' || c == 'O' || c == 'U'
+}
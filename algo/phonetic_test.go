@@ -0,0 +1,148 @@
+package algo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoundex(t *testing.T) {
+	// reference values from the US National Archives soundex coding guide
+	var tests = []struct {
+		in       string
+		expected string
+	}{
+		{"Robert", "R163"},
+		{"Rupert", "R163"},
+		{"Rubin", "R150"},
+		{"Ashcraft", "A261"},
+		{"Ashcroft", "A261"},
+		{"Tymczak", "T522"},
+		{"Pfister", "P236"},
+		{"Honeyman", "H555"},
+		{"Gutierrez", "G362"},
+		{"Jackson", "J250"},
+		{"Lee", "L000"},
+		{"o'hara", "O600"},
+		{"", ""},
+		{"123", ""},
+	}
+
+	for _, ut := range tests {
+		assert.Equal(t, ut.expected, Soundex(ut.in), fmt.Sprintf("Test %q", ut.in))
+	}
+}
+
+func TestMetaphone(t *testing.T) {
+	var tests = []struct {
+		in       string
+		expected string
+	}{
+		{"Thumb", "0M"},
+		{"Knight", "NT"},
+		{"Wright", "RT"},
+		{"Xavier", "SFR"},
+		{"Phone", "FN"},
+		{"Science", "SNS"},
+		{"Judge", "JJ"},
+		{"Smith", "SM0"},
+		{"Church", "XRX"},
+		{"Christ", "KRST"},
+		{"Aebersold", "EBRSLT"},
+		{"Whitney", "WTN"},
+		{"Sign", "SN"},
+		{"Signed", "SNT"},
+		{"", ""},
+	}
+
+	for _, ut := range tests {
+		assert.Equal(t, ut.expected, Metaphone(ut.in), fmt.Sprintf("Test %q", ut.in))
+	}
+}
+
+func TestDoubleMetaphone(t *testing.T) {
+	// reference values from Lawrence Philips' original article and implementation
+	var tests = []struct {
+		in        string
+		primary   string
+		alternate string
+	}{
+		{"Smith", "SM0", "XMT"},
+		{"Schmidt", "XMT", "SMT"},
+		{"Xavier", "SF", "SFR"},
+		{"Arnow", "ARN", "ARNF"},
+		{"Dumb", "TM", "TM"},
+		{"Knight", "NT", "NT"},
+		{"Caesar", "SSR", "SSR"},
+		{"Gough", "KF", "KF"},
+		{"Edge", "AJ", "AJ"},
+		{"Jankelowicz", "JNKL", "ANKL"},
+		{"Jose", "HS", "HS"},
+		{"Michael", "MKL", "MXL"},
+		{"Filipowicz", "FLPT", "FLPF"},
+		{"Czerny", "SRN", "XRN"},
+		{"Focaccia", "FKX", "FKX"},
+		{"Sugar", "XKR", "SKR"},
+		{"Zhao", "J", "J"},
+		{"Breaux", "PR", "PR"},
+		{"Accident", "AKST", "AKST"},
+		{"Campbell", "KMPL", "KMPL"},
+		{"Island", "ALNT", "ALNT"},
+		{"Rogier", "RJ", "RJR"},
+		{"Agnes", "AKNS", "ANS"},
+		{"Cabrillo", "KPRL", "KPR"},
+		{"Wasserman", "ASRM", "FSRM"},
+		{"Français", "FRNS", "FRNS"},
+		{"", "", ""},
+	}
+
+	for _, ut := range tests {
+		primary, alternate := DoubleMetaphone(ut.in)
+		msg := fmt.Sprintf("Test %q", ut.in)
+		assert.Equal(t, ut.primary, primary, msg)
+		assert.Equal(t, ut.alternate, alternate, msg)
+		assert.Equal(t, ut.primary, DoubleMetaphonePrimary(ut.in), msg)
+	}
+}
+
+func TestNYSIIS(t *testing.T) {
+	// reference values from the original NYSIIS paper examples
+	var tests = []struct {
+		in       string
+		expected string
+	}{
+		{"Brian", "BRAN"},
+		{"Brown", "BRAN"},
+		{"Brun", "BRAN"},
+		{"Capp", "CAP"},
+		{"Cope", "CAP"},
+		{"Kipp", "CAP"},
+		{"Dent", "DAD"},
+		{"Dane", "DAN"},
+		{"Dean", "DAN"},
+		{"Dionne", "DAN"},
+		{"Smith", "SNAT"},
+		{"Schmit", "SNAT"},
+		{"MacIntosh", "MCANT"},
+		{"Knight", "NAGT"},
+		{"Bishop", "BASAP"},
+		{"Mitchell", "MATCAL"},
+		{"Phillipson", "FALAPS"},
+		{"Greene", "GRAN"},
+		{"", ""},
+	}
+
+	for _, ut := range tests {
+		assert.Equal(t, ut.expected, NYSIIS(ut.in), fmt.Sprintf("Test %q", ut.in))
+	}
+}
+
+func TestPhoneticKey(t *testing.T) {
+	assert.Equal(t, "R163 B625", PhoneticKey(SoundexEncoder, "Robert  Brisson"))
+	assert.Equal(t, "SM0 JN", PhoneticKey(DoubleMetaphoneEncoder, "smith - john"))
+	assert.Equal(t, "", PhoneticKey(NYSIISEncoder, ""))
+
+	var enc PhoneticEncoder = PhoneticFunc(func(s string) string { return s + "!" })
+	assert.Equal(t, "a! b!", PhoneticKey(enc, "a b"))
+}
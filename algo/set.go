@@ -0,0 +1,73 @@
+package algo
+
+// Set holds distinct comparable values
+type Set[T comparable] map[T]struct{}
+
+// NewSet returns a set of the distinct values
+func NewSet[T comparable](values ...T) Set[T] {
+	s := make(Set[T], len(values))
+	for _, v := range values {
+		s[v] = struct{}{}
+	}
+	return s
+}
+
+// Has returns whether v is in the set
+func (s Set[T]) Has(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+// intersectionSize counts the values present in both sets, iterating the smaller one
+func intersectionSize[T comparable](a Set[T], b Set[T]) int {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	n := 0
+	for v := range a {
+		if b.Has(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// Dice returns the Sørensen–Dice coefficient 2|A∩B| / (|A|+|B|). Two empty sets are identical (1)
+func Dice[T comparable](a Set[T], b Set[T]) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	return 2 * float64(intersectionSize(a, b)) / float64(len(a)+len(b))
+}
+
+// Overlap returns the overlap (Szymkiewicz–Simpson) coefficient |A∩B| / min(|A|,|B|), which is 1 whenever a set contains the other.
+// Two empty sets are identical (1), while an empty set and a non-empty one have no overlap (0)
+func Overlap[T comparable](a Set[T], b Set[T]) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	smallest := min(len(a), len(b))
+	if smallest == 0 {
+		return 0
+	}
+	return float64(intersectionSize(a, b)) / float64(smallest)
+}
+
+// Tversky returns the Tversky index |A∩B| / (|A∩B| + alpha|A-B| + beta|B-A|), an asymmetric similarity where a is the prototype
+// and b the variant. alpha = beta = 1 is Jaccard, alpha = beta = 0.5 is Dice. Two empty sets are identical (1)
+func Tversky[T comparable](a Set[T], b Set[T], alpha float64, beta float64) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	intersect := float64(intersectionSize(a, b))
+	onlyA := float64(len(a)) - intersect
+	onlyB := float64(len(b)) - intersect
+
+	denominator := intersect + alpha*onlyA + beta*onlyB
+	if denominator == 0 {
+		return 0
+	}
+	return intersect / denominator
+}
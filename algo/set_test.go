@@ -0,0 +1,35 @@
+package algo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetMetrics(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+	empty := NewSet[int]()
+
+	t.Run("Dice", func(t *testing.T) {
+		assert.InDelta(t, 4.0/7, Dice(a, b), 1e-9)
+		assert.InDelta(t, 1.0, Dice(empty, empty), 1e-9)
+		assert.InDelta(t, 0.0, Dice(a, empty), 1e-9)
+	})
+
+	t.Run("Overlap", func(t *testing.T) {
+		assert.InDelta(t, 2.0/3, Overlap(a, b), 1e-9)
+		assert.InDelta(t, 1.0, Overlap(a, NewSet(2, 3)), 1e-9)
+		assert.InDelta(t, 1.0, Overlap(empty, empty), 1e-9)
+		assert.InDelta(t, 0.0, Overlap(a, empty), 1e-9)
+	})
+
+	t.Run("Tversky", func(t *testing.T) {
+		assert.InDelta(t, JaccardSet(a, b), Tversky(a, b, 1, 1), 1e-9)
+		assert.InDelta(t, Dice(a, b), Tversky(a, b, 0.5, 0.5), 1e-9)
+		// only the prototype's missing values are penalized
+		assert.InDelta(t, 2.0/4, Tversky(a, b, 1, 0), 1e-9)
+		assert.InDelta(t, 2.0/3, Tversky(a, b, 0, 1), 1e-9)
+		assert.InDelta(t, 1.0, Tversky(empty, empty, 1, 1), 1e-9)
+	})
+}
@@ -0,0 +1,43 @@
+package algo
+
+// soundexCodes maps each letter to its Soundex digit. Vowels, H, W and Y have none (0)
+var soundexCodes = [26]byte{
+	//A  B    C    D    E  F    G    H  I  J    K    L    M    N    O  P    Q    R    S    T    U  V    W  X    Y  Z
+	0, '1', '2', '3', 0, '1', '2', 0, 0, '2', '2', '4', '5', '5', 0, '1', '2', '6', '2', '3', 0, '1', 0, '2', 0, '2',
+}
+
+// Soundex returns the american Soundex code of s: its first letter followed by 3 digits, such as R163 for Robert.
+// Letters with the same code are only coded once when they are adjacent or only separated by H or W.
+// Non ascii letters are ignored, an empty string is returned when s has no letter
+func Soundex(s string) string {
+	letters := upperLetters(s)
+	if len(letters) == 0 {
+		return ""
+	}
+
+	code := make([]byte, 1, 4)
+	code[0] = letters[0]
+	last := soundexCodes[letters[0]-'A']
+	for _, c := range letters[1:] {
+		if len(code) == 4 {
+			break
+		}
+
+		digit := soundexCodes[c-'A']
+		switch {
+		case digit != 0:
+			if digit != last {
+				code = append(code, digit)
+			}
+			last = digit
+		case c != 'H' && c != 'W':
+			// vowels separate letters with the same code, H and W don't
+			last = 0
+		}
+	}
+
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
@@ -0,0 +1,68 @@
+package algo
+
+import (
+	"errors"
+	"math"
+)
+
+// Number is any signed, unsigned or floating point type vectors can hold
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+var (
+	ErrLengthMismatch = errors.New("algo: vectors must be of equal length")
+	ErrZeroVector     = errors.New("algo: vector has a zero magnitude")
+)
+
+// Dot returns the dot product of a and b. Values are accumulated as float64, so signed values are handled correctly
+// and large unsigned products don't overflow
+func Dot[T Number](a []T, b []T) (float64, error) {
+	if len(a) != len(b) {
+		return 0, ErrLengthMismatch
+	}
+
+	dot := 0.0
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot, nil
+}
+
+// Norm returns the euclidean norm (magnitude) of a
+func Norm[T Number](a []T) float64 {
+	sum := 0.0
+	for _, v := range a {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum)
+}
+
+// EuclideanDistance returns the straight line (L2) distance between a and b
+func EuclideanDistance[T Number](a []T, b []T) (float64, error) {
+	if len(a) != len(b) {
+		return 0, ErrLengthMismatch
+	}
+
+	sum := 0.0
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return math.Sqrt(sum), nil
+}
+
+// ManhattanDistance returns the sum of the absolute differences (L1) between a and b
+func ManhattanDistance[T Number](a []T, b []T) (float64, error) {
+	if len(a) != len(b) {
+		return 0, ErrLengthMismatch
+	}
+
+	sum := 0.0
+	for i := range a {
+		sum += math.Abs(float64(a[i]) - float64(b[i]))
+	}
+	return sum, nil
+}
@@ -0,0 +1,92 @@
+package algo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCosine(t *testing.T) {
+	t.Run("signed values", func(t *testing.T) {
+		s, err := Cosine([]int{1, -2, 3}, []int{-1, 2, -3})
+		assert.NoError(t, err)
+		assert.InDelta(t, -1.0, s, 1e-9)
+	})
+
+	t.Run("unsigned values", func(t *testing.T) {
+		s, err := Cosine([]uint32{1, 2, 0}, []uint32{2, 4, 0})
+		assert.NoError(t, err)
+		assert.InDelta(t, 1.0, s, 1e-9)
+	})
+
+	t.Run("orthogonal float values", func(t *testing.T) {
+		s, err := Cosine([]float32{1, 0}, []float32{0, 0.5})
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.0, s, 1e-9)
+	})
+
+	t.Run("length mismatch", func(t *testing.T) {
+		_, err := Cosine([]int{1, 2}, []int{1})
+		assert.ErrorIs(t, err, ErrLengthMismatch)
+	})
+
+	t.Run("zero vectors", func(t *testing.T) {
+		_, err := Cosine([]int{0, 0}, []int{1, 2})
+		assert.ErrorIs(t, err, ErrZeroVector)
+
+		_, err = Cosine([]int{}, []int{})
+		assert.ErrorIs(t, err, ErrZeroVector)
+	})
+}
+
+func TestDeprecatedCosine(t *testing.T) {
+	assert.InDelta(t, -1.0, CosineSimilarity([]int{1, -2}, []int{-1, 2}), 1e-9)
+	assert.InDelta(t, 1.0, CosineSimilarityUint16([]uint16{3, 4}, []uint16{6, 8}), 1e-9)
+	assert.True(t, math.IsNaN(CosineSimilarityUint32([]uint32{0}, []uint32{1})))
+	assert.Panics(t, func() { CosineSimilarityUint32([]uint32{1}, []uint32{1, 2}) })
+}
+
+func TestDot(t *testing.T) {
+	d, err := Dot([]int8{-1, 2, 3}, []int8{4, -5, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, d)
+
+	d, err = Dot([]uint64{math.MaxUint32, 2}, []uint64{math.MaxUint32, 2})
+	assert.NoError(t, err)
+	assert.InDelta(t, float64(math.MaxUint32)*float64(math.MaxUint32)+4, d, 1)
+
+	_, err = Dot([]int{1}, []int{})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
+
+func TestNorm(t *testing.T) {
+	assert.Equal(t, 5.0, Norm([]int{-3, 4}))
+	assert.Equal(t, 0.0, Norm([]float64{}))
+}
+
+func TestEuclideanDistance(t *testing.T) {
+	d, err := EuclideanDistance([]int{0, 0}, []int{-3, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, d)
+
+	d, err = EuclideanDistance([]uint8{1, 5}, []uint8{5, 1})
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(32), d, 1e-9)
+
+	_, err = EuclideanDistance([]int{1}, []int{1, 2})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
+
+func TestManhattanDistance(t *testing.T) {
+	d, err := ManhattanDistance([]uint16{1, 5}, []uint16{5, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 8.0, d)
+
+	d, err = ManhattanDistance([]float64{-1.5, 2}, []float64{1.5, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, d)
+
+	_, err = ManhattanDistance([]int{1}, []int{1, 2})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
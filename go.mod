@@ -1,6 +1,6 @@
 module gowtools
 
-go 1.22
+go 1.23
 
 require (
 	github.com/jmoiron/sqlx v1.3.5
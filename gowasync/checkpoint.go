@@ -0,0 +1,92 @@
+package gowasync
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// CheckpointStore records the work units completed by a work group, so that a rerun with the same store skips them.
+// Units are identified by their index, so reruns must be given the same chunks, or a stream yielding them in the same order
+type CheckpointStore interface {
+	// Load returns the indexes of the completed work units
+	Load() ([]int, error)
+	// Mark records the work unit at index as completed. It is called by multiple workers concurrently
+	Mark(index int) error
+}
+
+// FileCheckpoint is a CheckpointStore appending the completed work units to a file, one index per line
+type FileCheckpoint struct {
+	mutex sync.Mutex
+	path  string
+	file  *os.File
+}
+
+// NewFileCheckpoint returns a store recording in the file at path, created if it doesn't exist.
+// Remove the file to start over
+func NewFileCheckpoint(path string) (*FileCheckpoint, error) {
+	if err := truncatePartialLine(path); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileCheckpoint{path: path, file: f}, nil
+}
+
+// truncatePartialLine removes the end of the file after its last newline, which was partially written when
+// the process died, so that the next index is not appended to it
+func truncatePartialLine(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	if complete == len(data) {
+		return nil
+	}
+	return os.Truncate(path, int64(complete))
+}
+
+func (c *FileCheckpoint) Load() ([]int, error) {
+	f, err := os.Open(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var completed []int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		index, err := strconv.Atoi(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("gowasync: invalid checkpoint line %q", scanner.Text())
+		}
+		completed = append(completed, index)
+	}
+	return completed, scanner.Err()
+}
+
+func (c *FileCheckpoint) Mark(index int) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, err := c.file.WriteString(strconv.Itoa(index) + "\n")
+	return err
+}
+
+// Close closes the file
+func (c *FileCheckpoint) Close() error {
+	return c.file.Close()
+}
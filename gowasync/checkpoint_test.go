@@ -0,0 +1,105 @@
+package gowasync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkGroupCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backfill.checkpoint")
+	failing := map[int]bool{3: true, 7: true}
+
+	run := func() ([]int, *ErrorSyncGroup) {
+		store, err := NewFileCheckpoint(path)
+		assert.NoError(t, err)
+		defer store.Close()
+
+		var mutex sync.Mutex
+		var processed []int
+		wg := NewWorkGroup(3, givenChunks(10, 1), func(data []int) error {
+			mutex.Lock()
+			processed = append(processed, data[0])
+			mutex.Unlock()
+			if failing[data[0]] {
+				return errors.New("failed")
+			}
+			return nil
+		})
+		wg.SetCheckpoint(store)
+
+		errs := wg.AwaitExecute()
+		sort.Ints(processed)
+		return processed, errs
+	}
+
+	processed, errs := run()
+	assert.Len(t, processed, 10)
+	assert.Equal(t, 2, errs.Len())
+
+	// the rerun only processes the failed units
+	failing = map[int]bool{}
+	processed, errs = run()
+	assert.Nil(t, errs.Err())
+	assert.Equal(t, []int{3, 7}, processed)
+
+	processed, _ = run()
+	assert.Empty(t, processed)
+
+	t.Run("the progress total excludes the completed units", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "progress.checkpoint")
+		assert.NoError(t, os.WriteFile(path, []byte("0\n1\n2\n"), 0o644))
+		store, err := NewFileCheckpoint(path)
+		assert.NoError(t, err)
+		defer store.Close()
+
+		r := &recordingReporter{}
+		wg := NewWorkGroup(2, givenChunks(10, 1), func(data []int) error {
+			return nil
+		})
+		wg.SetCheckpoint(store)
+		wg.SetProgressReporter(r)
+		wg.AwaitExecute()
+
+		assert.Equal(t, 7, r.started[0].Total)
+		assert.Equal(t, 7, r.finished[0].Done)
+		assert.Equal(t, time.Duration(0), r.finished[0].ETA)
+	})
+}
+
+func TestFileCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	store, err := NewFileCheckpoint(path)
+	assert.NoError(t, err)
+
+	completed, err := store.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, completed)
+
+	assert.NoError(t, store.Mark(4))
+	assert.NoError(t, store.Mark(1))
+	assert.NoError(t, store.Close())
+
+	// a line partially written when the process died is ignored
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	assert.NoError(t, err)
+	_, err = f.WriteString("12")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	store, err = NewFileCheckpoint(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	assert.NoError(t, store.Mark(3))
+
+	completed, err = store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 1, 3}, completed)
+}
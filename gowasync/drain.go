@@ -0,0 +1,123 @@
+package gowasync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStopped is added to the errors of an execution stopped before processing all its work units
+var ErrStopped = errors.New("gowasync: work group stopped")
+
+// Summary is the state of the work units of an execution, as returned by Drain
+type Summary struct {
+	Completed  int // work units processed, successfully or not
+	InFlight   int // work units still being processed
+	NotStarted int // work units never started, -1 when unknown such as for streams
+}
+
+// execution is the state of a running AwaitExecuteContext, which can be stopped from other goroutines
+type execution struct {
+	cancel       context.CancelFunc // cancels the work units
+	stopDispatch context.CancelFunc // stops starting new work units
+	stopped      atomic.Bool
+	finished     chan struct{}
+
+	total   int // work units to process, -1 when unknown
+	started atomic.Int64
+	done    atomic.Int64
+}
+
+func newExecution(cancel context.CancelFunc, stopDispatch context.CancelFunc, total int) *execution {
+	return &execution{
+		cancel:       cancel,
+		stopDispatch: stopDispatch,
+		finished:     make(chan struct{}),
+		total:        total,
+	}
+}
+
+func (e *execution) stop() {
+	e.stopped.Store(true)
+	e.stopDispatch()
+}
+
+func (e *execution) summary() Summary {
+	done := int(e.done.Load())
+	started := int(e.started.Load())
+	s := Summary{Completed: done, InFlight: started - done, NotStarted: -1}
+	if e.total >= 0 {
+		s.NotStarted = e.total - started
+	}
+	return s
+}
+
+func (e *execution) isFinished() bool {
+	select {
+	case <-e.finished:
+		return true
+	default:
+		return false
+	}
+}
+
+// executions holds the last execution of a work group, and the stop requested while it was not executing
+type executions struct {
+	mutex         sync.Mutex
+	current       *execution
+	stopRequested bool
+}
+
+// start makes exec the current execution, stopping it right away if a stop was requested since the last execution
+func (e *executions) start(exec *execution) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.current = exec
+	if e.stopRequested {
+		e.stopRequested = false
+		exec.stop()
+	}
+}
+
+// stop stops the current execution, or requests the next one to stop when the group is not executing.
+// It returns the last execution, nil if there was none
+func (e *executions) stop() *execution {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.current == nil || e.current.isFinished() {
+		e.stopRequested = true
+	} else {
+		e.current.stop()
+	}
+	return e.current
+}
+
+// Stop stops dispatching work units, letting the ones in flight finish. The execution then returns with ErrStopped
+// if units were left unprocessed. It can be called from any goroutine. When the group is not executing, such as
+// during the startup of the program, the next execution is stopped as soon as it starts
+func (t *Group[T]) Stop() {
+	t.executions.stop()
+}
+
+// Drain stops the execution like Stop, then waits for the work units in flight to finish. Those still running after
+// timeout have their context canceled, and are returned as in flight. When the group is not executing, the next
+// execution is stopped like with Stop, and the last one is summarized
+func (t *Group[T]) Drain(timeout time.Duration) Summary {
+	exec := t.executions.stop()
+	if exec == nil {
+		return Summary{}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-exec.finished:
+		return exec.summary()
+	case <-timer.C:
+		summary := exec.summary()
+		exec.cancel()
+		return summary
+	}
+}
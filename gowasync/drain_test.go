@@ -0,0 +1,118 @@
+package gowasync
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkGroupDrain(t *testing.T) {
+	var processed int64
+	wg := NewWorkGroup(2, givenChunks(100, 1), func(data []int) error {
+		time.Sleep(2 * time.Millisecond)
+		atomic.AddInt64(&processed, 1)
+		return nil
+	})
+
+	summaries := make(chan Summary)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		summaries <- wg.Drain(time.Second)
+	}()
+
+	errs := wg.AwaitExecute()
+	summary := <-summaries
+
+	assert.ErrorIs(t, errs, ErrStopped)
+	assert.Equal(t, 1, errs.Len())
+	assert.Equal(t, 0, summary.InFlight)
+	assert.Equal(t, int(processed), summary.Completed)
+	assert.Greater(t, summary.NotStarted, 0)
+	assert.Equal(t, 100, summary.Completed+summary.NotStarted)
+
+	t.Run("in flight units are canceled after the timeout", func(t *testing.T) {
+		var started int64
+		wg := NewWorkGroupContext(3, givenChunks(10, 1), func(ctx context.Context, data []int) error {
+			atomic.AddInt64(&started, 1)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		summaries := make(chan Summary)
+		go func() {
+			for atomic.LoadInt64(&started) < 3 {
+				time.Sleep(time.Millisecond)
+			}
+			summaries <- wg.Drain(5 * time.Millisecond)
+		}()
+
+		errs := wg.AwaitExecute()
+		assert.Equal(t, Summary{Completed: 0, InFlight: 3, NotStarted: 7}, <-summaries)
+		assert.ErrorIs(t, errs, ErrStopped)
+		assert.ErrorIs(t, errs, context.Canceled)
+	})
+
+	t.Run("streams have an unknown number of units not started", func(t *testing.T) {
+		wg := NewStreamWorkGroup(1, 1, givenItems(1000), func(context.Context, []int) error {
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			wg.Stop()
+		}()
+		assert.ErrorIs(t, wg.AwaitExecute(), ErrStopped)
+		summary := wg.Drain(time.Second)
+		assert.Equal(t, -1, summary.NotStarted)
+		assert.Less(t, summary.Completed, 1000)
+	})
+
+	t.Run("a stop before the execution stops the next one", func(t *testing.T) {
+		var processed int64
+		wg := NewWorkGroup(1, givenChunks(3, 1), func(data []int) error {
+			atomic.AddInt64(&processed, 1)
+			return nil
+		})
+		wg.Stop()
+
+		assert.ErrorIs(t, wg.AwaitExecute(), ErrStopped)
+		assert.Equal(t, int64(0), processed)
+
+		// the request is consumed by the stopped execution
+		assert.Nil(t, wg.AwaitExecute().Err())
+		assert.Equal(t, int64(3), processed)
+		assert.Equal(t, Summary{Completed: 3}, wg.Drain(time.Second))
+
+		t.Run("drain too", func(t *testing.T) {
+			wg := NewWorkGroup(1, givenChunks(3, 1), func(data []int) error {
+				return nil
+			})
+			assert.Equal(t, Summary{}, wg.Drain(time.Second))
+			assert.ErrorIs(t, wg.AwaitExecute(), ErrStopped)
+		})
+	})
+
+	t.Run("a stop after the last unit is not an error", func(t *testing.T) {
+		var wg *Group[int]
+		wg = NewWorkGroup(1, givenChunks(3, 1), func(data []int) error {
+			if data[0] == 2 {
+				wg.Stop()
+			}
+			return nil
+		})
+		assert.Nil(t, wg.AwaitExecute().Err())
+
+		var stream *Group[int]
+		stream = NewStreamWorkGroup(1, 1, givenItems(3), func(_ context.Context, data []int) error {
+			if data[0] == 2 {
+				stream.Stop()
+			}
+			return nil
+		})
+		assert.Nil(t, stream.AwaitExecute().Err())
+	})
+}
@@ -1,21 +1,125 @@
 package gowasync
 
-import "sync"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
 
+// ErrorSyncGroup collects errors from multiple goroutines. It implements error, but the Err method should be used
+// to check whether errors occurred, since a nil *ErrorSyncGroup stored in an error interface is not nil
 type ErrorSyncGroup struct {
 	mutex sync.Mutex
 	errs  []error
+	limit int // maximum number of errors stored, none when 0
+	count int // number of errors added, including those over the limit
 }
 
+// Add adds e to the group, nil being ignored
 func (eg *ErrorSyncGroup) Add(e error) {
+	if e == nil {
+		return
+	}
+	eg.mutex.Lock()
+	eg.count++
+	if eg.limit <= 0 || len(eg.errs) < eg.limit {
+		eg.errs = append(eg.errs, e)
+	}
+	eg.mutex.Unlock()
+}
+
+// SetLimit caps the number of errors stored to n, none when 0. Errors over it are only counted, so that
+// a job failing on millions of items doesn't hold all their errors in memory
+func (eg *ErrorSyncGroup) SetLimit(n int) {
 	eg.mutex.Lock()
-	eg.errs = append(eg.errs, e)
+	eg.limit = n
 	eg.mutex.Unlock()
 }
 
 func (eg *ErrorSyncGroup) GetFirst() error {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
 	if len(eg.errs) > 0 {
 		return eg.errs[0]
 	}
 	return nil
 }
+
+// Errors returns the stored errors, in the order they were added
+func (eg *ErrorSyncGroup) Errors() []error {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+	return slices.Clone(eg.errs)
+}
+
+// Len returns the number of errors added, including those not stored because of the limit
+func (eg *ErrorSyncGroup) Len() int {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+	return eg.count
+}
+
+// Err returns the stored errors joined with errors.Join, nil when there are none
+func (eg *ErrorSyncGroup) Err() error {
+	return errors.Join(eg.Errors()...)
+}
+
+func (eg *ErrorSyncGroup) Error() string {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	if len(eg.errs) == 0 {
+		return "gowasync: no errors"
+	}
+	msg := errors.Join(eg.errs...).Error()
+	if dropped := eg.count - len(eg.errs); dropped > 0 {
+		msg += fmt.Sprintf("\n(and %d more errors)", dropped)
+	}
+	return msg
+}
+
+// Unwrap returns the stored errors, so errors.Is and errors.As match any of them
+func (eg *ErrorSyncGroup) Unwrap() []error {
+	return eg.Errors()
+}
+
+// UnitError is the error of a work unit, as added to the ErrorSyncGroup of an execution
+type UnitError struct {
+	Unit   int // index of the work unit's chunk
+	Worker int // worker which processed it, -1 when not processed by a work group
+	Err    error
+}
+
+func (e *UnitError) Error() string {
+	if e.Worker < 0 {
+		return fmt.Sprintf("gowasync: work unit %d: %v", e.Unit, e.Err)
+	}
+	return fmt.Sprintf("gowasync: work unit %d on worker %d: %v", e.Unit, e.Worker, e.Err)
+}
+
+func (e *UnitError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is a panic recovered while processing a work unit
+type PanicError struct {
+	Unit  int    // index of the work unit's chunk, -1 for a future
+	Value any    // value given to panic
+	Stack []byte // stack trace of the panic, not part of the message to keep joined errors readable
+}
+
+func (e *PanicError) Error() string {
+	if e.Unit < 0 {
+		return fmt.Sprintf("gowasync: panicked: %v", e.Value)
+	}
+	return fmt.Sprintf("gowasync: work unit %d panicked: %v", e.Unit, e.Value)
+}
+
+// Unwrap returns the value given to panic when it is an error, so errors.Is and errors.As can match it
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
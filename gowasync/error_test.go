@@ -2,6 +2,7 @@ package gowasync
 
 import (
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"math/rand"
 	"strconv"
@@ -42,3 +43,44 @@ func TestErrorSyncGroup(t *testing.T) {
 	})
 
 }
+
+func TestErrorSyncGroupErrors(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+
+	errGr := &ErrorSyncGroup{}
+	assert.Nil(t, errGr.Err())
+	assert.Equal(t, 0, errGr.Len())
+
+	errGr.Add(errA)
+	errGr.Add(nil)
+	errGr.Add(&UnitError{Unit: 3, Worker: 1, Err: errB})
+
+	assert.Equal(t, 2, errGr.Len())
+	assert.Len(t, errGr.Errors(), 2)
+	assert.EqualError(t, errGr.Err(), "a\ngowasync: work unit 3 on worker 1: b")
+	assert.EqualError(t, errGr, "a\ngowasync: work unit 3 on worker 1: b")
+	assert.ErrorIs(t, errGr, errB)
+	assert.ErrorIs(t, errGr.Err(), errA)
+
+	var unitErr *UnitError
+	assert.ErrorAs(t, errGr, &unitErr)
+	assert.Equal(t, 3, unitErr.Unit)
+
+	t.Run("empty group can be printed", func(t *testing.T) {
+		var err error = &ErrorSyncGroup{}
+		assert.Equal(t, "gowasync: no errors", err.Error())
+		assert.Equal(t, "gowasync: no errors", fmt.Sprint(err))
+	})
+
+	t.Run("errors over the limit are only counted", func(t *testing.T) {
+		errGr := &ErrorSyncGroup{}
+		errGr.SetLimit(2)
+		for i := 0; i < 5; i++ {
+			errGr.Add(errors.New("error " + strconv.Itoa(i)))
+		}
+
+		assert.Equal(t, 5, errGr.Len())
+		assert.Len(t, errGr.Errors(), 2)
+		assert.EqualError(t, errGr, "error 0\nerror 1\n(and 3 more errors)")
+	})
+}
@@ -0,0 +1,164 @@
+package gowasync
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// Future is the result of an asynchronous computation
+type Future[T any] struct {
+	done  chan struct{}
+	once  sync.Once
+	value T
+	err   error
+}
+
+// Go runs fn in a goroutine and returns the future of its result. A panic of fn is recovered into a *PanicError
+func Go[T any](ctx context.Context, fn func(context.Context) (T, error)) *Future[T] {
+	f, complete := NewPromise[T]()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				var zero T
+				complete(zero, &PanicError{Unit: -1, Value: r, Stack: debug.Stack()})
+			}
+		}()
+		complete(fn(ctx))
+	}()
+	return f
+}
+
+// NewPromise returns a future along with the function completing it, which can be called by any goroutine.
+// Only the first completion is kept
+func NewPromise[T any]() (*Future[T], func(T, error)) {
+	f := &Future[T]{done: make(chan struct{})}
+	return f, func(v T, err error) {
+		f.once.Do(func() {
+			f.value, f.err = v, err
+			close(f.done)
+		})
+	}
+}
+
+// Done returns a channel closed once the future is completed
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await waits for the result, or returns ctx's error if it is canceled first
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// All waits for all the futures, returning their values in order. The errors are reported as *UnitError
+// with the index of their future as Unit, along with ctx's error if it is canceled first
+func All[T any](ctx context.Context, futures ...*Future[T]) ([]T, *ErrorSyncGroup) {
+	values := make([]T, len(futures))
+	errs := &ErrorSyncGroup{}
+	for i, f := range futures {
+		v, err := f.Await(ctx)
+		if ctx.Err() != nil {
+			errs.Add(ctx.Err())
+			break
+		}
+		if err != nil {
+			errs.Add(&UnitError{Unit: i, Worker: -1, Err: err})
+		}
+		values[i] = v
+	}
+	return values, errs
+}
+
+// Any returns the value of the first future to succeed. When none does, the errors of all of them
+// are reported as in All. Futures still running are not canceled
+func Any[T any](ctx context.Context, futures ...*Future[T]) (T, *ErrorSyncGroup) {
+	var zero T
+	errs := &ErrorSyncGroup{}
+	completed := completions(futures)
+	for range futures {
+		select {
+		case i := <-completed:
+			if futures[i].err == nil {
+				return futures[i].value, &ErrorSyncGroup{}
+			}
+			errs.Add(&UnitError{Unit: i, Worker: -1, Err: futures[i].err})
+		case <-ctx.Done():
+			errs.Add(ctx.Err())
+			return zero, errs
+		}
+	}
+	return zero, errs
+}
+
+// Race returns the result of the first future to complete, whether it succeeded or not
+func Race[T any](ctx context.Context, futures ...*Future[T]) (T, *ErrorSyncGroup) {
+	var zero T
+	errs := &ErrorSyncGroup{}
+	if len(futures) == 0 {
+		return zero, errs
+	}
+
+	select {
+	case i := <-completions(futures):
+		if futures[i].err != nil {
+			errs.Add(&UnitError{Unit: i, Worker: -1, Err: futures[i].err})
+		}
+		return futures[i].value, errs
+	case <-ctx.Done():
+		errs.Add(ctx.Err())
+		return zero, errs
+	}
+}
+
+// completions returns a channel receiving the index of each future as it completes
+func completions[T any](futures []*Future[T]) <-chan int {
+	completed := make(chan int, len(futures))
+	for i, f := range futures {
+		go func() {
+			<-f.done
+			completed <- i
+		}()
+	}
+	return completed
+}
+
+// ParallelMap maps items with fn on n goroutines, returning the results in order. The errors are reported as *UnitError
+// with the index of their item as Unit, the results of failed items being zero values
+func ParallelMap[T any, R any](ctx context.Context, items []T, n int, fn func(context.Context, T) (R, error)) ([]R, *ErrorSyncGroup) {
+	mg := NewMapGroupContext(uint32(max(n, 1)), singleItemChunks(items), func(ctx context.Context, data []T) ([]R, error) {
+		r, err := fn(ctx, data[0])
+		return []R{r}, err
+	})
+	res := mg.AwaitExecuteContext(ctx)
+
+	results := make([]R, len(items))
+	for i, r := range res.Results {
+		if len(r) == 1 {
+			results[i] = r[0]
+		}
+	}
+	return results, res.Errs
+}
+
+// ParallelForEach calls fn on each item on n goroutines. The errors are reported as in ParallelMap
+func ParallelForEach[T any](ctx context.Context, items []T, n int, fn func(context.Context, T) error) *ErrorSyncGroup {
+	wg := NewWorkGroupContext(uint32(max(n, 1)), singleItemChunks(items), func(ctx context.Context, data []T) error {
+		return fn(ctx, data[0])
+	})
+	return wg.AwaitExecuteContext(ctx)
+}
+
+func singleItemChunks[T any](items []T) [][]T {
+	chunks := make([][]T, len(items))
+	for i := range items {
+		chunks[i] = items[i : i+1]
+	}
+	return chunks
+}
@@ -0,0 +1,144 @@
+package gowasync
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func givenFuture[T any](v T, err error, delay time.Duration) *Future[T] {
+	return Go(context.Background(), func(context.Context) (T, error) {
+		time.Sleep(delay)
+		return v, err
+	})
+}
+
+func TestFuture(t *testing.T) {
+	ctx := context.Background()
+
+	v, err := givenFuture(42, nil, time.Millisecond).Await(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+
+	t.Run("await is canceled with its context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		_, err := givenFuture(1, nil, time.Second).Await(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("panics are recovered", func(t *testing.T) {
+		f := Go(ctx, func(context.Context) (int, error) {
+			panic("boom")
+		})
+		_, err := f.Await(ctx)
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "boom", panicErr.Value)
+	})
+
+	t.Run("promises keep their first completion", func(t *testing.T) {
+		f, complete := NewPromise[string]()
+		go complete("first", nil)
+		<-f.Done()
+		complete("second", errors.New("late"))
+
+		v, err := f.Await(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", v)
+	})
+}
+
+func TestFutureCombinators(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	t.Run("all", func(t *testing.T) {
+		values, errs := All(ctx,
+			givenFuture(1, nil, 3*time.Millisecond),
+			givenFuture(0, errFailed, time.Millisecond),
+			givenFuture(3, nil, 0),
+		)
+		assert.Equal(t, []int{1, 0, 3}, values)
+		assert.Equal(t, 1, errs.Len())
+		assert.ErrorIs(t, errs, errFailed)
+		assert.EqualError(t, errs.GetFirst(), "gowasync: work unit 1: failed")
+	})
+
+	t.Run("any", func(t *testing.T) {
+		v, errs := Any(ctx,
+			givenFuture(0, errFailed, 0),
+			givenFuture(2, nil, 5*time.Millisecond),
+			givenFuture(3, nil, time.Second),
+		)
+		assert.Nil(t, errs.Err())
+		assert.Equal(t, 2, v)
+
+		_, errs = Any(ctx, givenFuture(0, errFailed, 0), givenFuture(0, errFailed, time.Millisecond))
+		assert.Equal(t, 2, errs.Len())
+	})
+
+	t.Run("race", func(t *testing.T) {
+		_, errs := Race(ctx, givenFuture(1, nil, time.Second), givenFuture(0, errFailed, time.Millisecond))
+		assert.ErrorIs(t, errs, errFailed)
+
+		v, errs := Race(ctx, givenFuture(1, nil, time.Second), givenFuture(2, nil, time.Millisecond))
+		assert.Nil(t, errs.Err())
+		assert.Equal(t, 2, v)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		_, errs := Race(ctx, givenFuture(1, nil, time.Second))
+		assert.ErrorIs(t, errs, context.DeadlineExceeded)
+	})
+}
+
+func TestParallelMap(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	var active, maxActive int64
+	results, errs := ParallelMap(context.Background(), items, 4, func(_ context.Context, v int) (string, error) {
+		cur := atomic.AddInt64(&active, 1)
+		defer atomic.AddInt64(&active, -1)
+		for {
+			m := atomic.LoadInt64(&maxActive)
+			if cur <= m || atomic.CompareAndSwapInt64(&maxActive, m, cur) {
+				break
+			}
+		}
+		if v == 13 {
+			return "", errors.New("unlucky")
+		}
+		return strconv.Itoa(v), nil
+	})
+
+	assert.LessOrEqual(t, maxActive, int64(4))
+	assert.Len(t, results, 100)
+	assert.Equal(t, "12", results[12])
+	assert.Equal(t, "", results[13])
+	assert.Equal(t, "99", results[99])
+
+	var unitErr *UnitError
+	assert.ErrorAs(t, errs.Err(), &unitErr)
+	assert.Equal(t, 13, unitErr.Unit)
+}
+
+func TestParallelForEach(t *testing.T) {
+	var sum int64
+	errs := ParallelForEach(context.Background(), []int64{1, 2, 3, 4}, 2, func(_ context.Context, v int64) error {
+		atomic.AddInt64(&sum, v)
+		return nil
+	})
+	assert.Nil(t, errs.Err())
+	assert.Equal(t, int64(10), sum)
+}
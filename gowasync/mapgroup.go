@@ -0,0 +1,82 @@
+package gowasync
+
+import "context"
+
+// MapGroup is a work group whose process returns results for each chunk of data
+type MapGroup[T any, R any] struct {
+	*Group[T]
+	mapper func(context.Context, []T) ([]R, error)
+}
+
+// MapResult holds the outcome of a MapGroup execution
+type MapResult[R any] struct {
+	Results [][]R           // results of each chunk, in the order of the chunks. nil for the chunks that failed or were skipped
+	Errors  []error         // error of each chunk, in the order of the chunks. nil for the chunks that succeeded or were skipped
+	Skipped []bool          // whether each chunk was skipped because the checkpoint already held it (see SetCheckpoint)
+	Errs    *ErrorSyncGroup // every error of the execution, including the context's
+}
+
+// NewMapGroup returns a work group mapping each chunk of data to results with process
+func NewMapGroup[T any, R any](workers uint32, dataChunked [][]T, process func([]T) ([]R, error)) *MapGroup[T, R] {
+	return NewMapGroupContext(workers, dataChunked, func(_ context.Context, data []T) ([]R, error) {
+		return process(data)
+	})
+}
+
+// NewMapGroupContext returns a map group like NewMapGroup, whose process receives the execution context
+func NewMapGroupContext[T any, R any](workers uint32, dataChunked [][]T, process func(context.Context, []T) ([]R, error)) *MapGroup[T, R] {
+	return &MapGroup[T, R]{
+		Group:  newGroup(workers, len(dataChunked), sliceSource(dataChunked), nil),
+		mapper: process,
+	}
+}
+
+// SetCheckpoint skips the chunks store already holds (see Group). They are flagged in Skipped and their Results are nil
+// since they are not mapped again: the mapper must persist its results itself for a resumed execution to be complete
+func (g *MapGroup[T, R]) SetCheckpoint(store CheckpointStore) {
+	g.Group.SetCheckpoint(store)
+}
+
+func (g *MapGroup[T, R]) AwaitExecute() MapResult[R] {
+	return g.AwaitExecuteContext(context.Background())
+}
+
+// AwaitExecuteContext maps all the chunks, unless ctx is canceled first (see Group)
+func (g *MapGroup[T, R]) AwaitExecuteContext(ctx context.Context) MapResult[R] {
+	res := MapResult[R]{
+		Results: make([][]R, g.total),
+		Errors:  make([]error, g.total),
+		Skipped: make([]bool, g.total),
+	}
+
+	// results are committed by the workers, each unit only writing at its own index so no locking is needed
+	g.process = func(ctx context.Context, index int, data []T) (func(), error) {
+		r, err := g.mapper(ctx, data)
+		return func() {
+			res.Results[index] = r
+		}, err
+	}
+	g.onUnitDone = func(index int, err error) {
+		res.Errors[index] = err
+	}
+	g.onCheckpointed = func(index int) {
+		res.Skipped[index] = true
+	}
+
+	res.Errs = g.Group.AwaitExecuteContext(ctx)
+	return res
+}
+
+// Flatten returns the results of all the chunks in a single slice, in order.
+// The chunks without results, such as the Skipped ones, are left out
+func (r MapResult[R]) Flatten() []R {
+	n := 0
+	for _, chunk := range r.Results {
+		n += len(chunk)
+	}
+	flat := make([]R, 0, n)
+	for _, chunk := range r.Results {
+		flat = append(flat, chunk...)
+	}
+	return flat
+}
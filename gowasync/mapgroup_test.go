@@ -0,0 +1,97 @@
+package gowasync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapGroup(t *testing.T) {
+	mg := NewMapGroup(4, givenChunks(20, 5), func(data []int) ([]string, error) {
+		out := make([]string, len(data))
+		for i, v := range data {
+			out[i] = strconv.Itoa(v)
+		}
+		return out, nil
+	})
+
+	res := mg.AwaitExecute()
+	assert.Nil(t, res.Errs.GetFirst())
+	assert.Len(t, res.Results, 20)
+	assert.Equal(t, []string{"5", "6", "7", "8", "9"}, res.Results[1])
+
+	flat := res.Flatten()
+	assert.Len(t, flat, 100)
+	for i, v := range flat {
+		assert.Equal(t, strconv.Itoa(i), v)
+	}
+
+	t.Run("errors are reported per chunk", func(t *testing.T) {
+		mg := NewMapGroup(2, givenChunks(6, 1), func(data []int) ([]int, error) {
+			switch data[0] {
+			case 2:
+				return nil, errors.New("failed")
+			case 4:
+				panic("bad record")
+			}
+			return []int{data[0] * 2}, nil
+		})
+
+		res := mg.AwaitExecute()
+		assert.Len(t, res.Errs.errs, 2)
+		assert.Equal(t, [][]int{{0}, {2}, nil, {6}, nil, {10}}, res.Results)
+		assert.EqualError(t, res.Errors[2], "failed")
+		assert.IsType(t, &PanicError{}, res.Errors[4])
+		for _, i := range []int{0, 1, 3, 5} {
+			assert.Nil(t, res.Errors[i])
+		}
+		assert.Equal(t, []int{0, 2, 6, 10}, res.Flatten())
+	})
+}
+
+func TestMapGroupUnitTimeout(t *testing.T) {
+	slow := make(chan struct{})
+	finished := make(chan struct{})
+	mg := NewMapGroup(2, givenChunks(4, 1), func(data []int) ([]string, error) {
+		if data[0] == 1 {
+			// ignores the timeout, returning a result once the execution gave up on it
+			<-slow
+			defer close(finished)
+		}
+		return []string{strconv.Itoa(data[0])}, nil
+	})
+	mg.SetUnitTimeout(5 * time.Millisecond)
+
+	res := mg.AwaitExecute()
+	close(slow)
+	<-finished
+
+	assert.Equal(t, 1, res.Errs.Len())
+	assert.ErrorIs(t, res.Errors[1], ErrUnitTimeout)
+	assert.Nil(t, res.Results[1])
+	assert.Equal(t, []string{"2"}, res.Results[2])
+}
+
+func TestMapGroupCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map.checkpoint")
+	assert.NoError(t, os.WriteFile(path, []byte("1\n"), 0o644))
+	store, err := NewFileCheckpoint(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	mg := NewMapGroup(2, givenChunks(3, 1), func(data []int) ([]string, error) {
+		return []string{strconv.Itoa(data[0])}, nil
+	})
+	mg.SetCheckpoint(store)
+
+	res := mg.AwaitExecute()
+	assert.Nil(t, res.Errs.Err())
+	assert.Equal(t, [][]string{{"0"}, nil, {"2"}}, res.Results)
+	assert.Equal(t, []bool{false, true, false}, res.Skipped)
+	assert.Equal(t, []string{"0", "2"}, res.Flatten())
+}
@@ -0,0 +1,247 @@
+package gowasync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Pipeline chains stages processing items concurrently, each with its own workers, connected by bounded channels.
+// The first error cancels the whole pipeline. Stages are added with Source, Stage and Sink before calling Wait
+type Pipeline struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	start  time.Time
+	wg     sync.WaitGroup
+	errs   *ErrorSyncGroup
+
+	mutex  sync.Mutex
+	stages []*stageStats
+}
+
+// Stream is the output of a pipeline stage, to be consumed by a single following stage
+type Stream[T any] struct {
+	p *Pipeline
+	c <-chan T
+}
+
+// StageError is the error of an item in a pipeline stage
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("gowasync: stage %s: %v", e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// StageStats are the throughput statistics of a pipeline stage
+type StageStats struct {
+	Name      string
+	Workers   int
+	Processed int64         // items processed successfully
+	Failed    int64         // items which failed
+	Elapsed   time.Duration // since the start of the pipeline, until the stage finished
+	Rate      float64       // processed items per second
+}
+
+type stageStats struct {
+	name      string
+	workers   int
+	processed atomic.Int64
+	failed    atomic.Int64
+	end       atomic.Int64 // unix nanoseconds at which the stage finished, 0 while running
+}
+
+// NewPipeline returns an empty pipeline, canceled with ctx
+func NewPipeline(ctx context.Context) *Pipeline {
+	p := &Pipeline{
+		parent: ctx,
+		start:  time.Now(),
+		errs:   &ErrorSyncGroup{},
+	}
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	return p
+}
+
+// Source adds a stage producing items with produce, which calls emit for each of them. emit blocks while the
+// buffer of the stage is full, and returns false when the pipeline is canceled, in which case produce should return
+func Source[T any](p *Pipeline, name string, buffer int, produce func(ctx context.Context, emit func(T) bool) error) Stream[T] {
+	out := make(chan T, buffer)
+	s := p.addStage(name, 1)
+	emit := func(v T) bool {
+		select {
+		case out <- v:
+			s.processed.Add(1)
+			return true
+		case <-p.ctx.Done():
+			return false
+		}
+	}
+
+	p.run(s, func() {
+		if err := p.call(func() error { return produce(p.ctx, emit) }); err != nil {
+			p.fail(s, err)
+		}
+	}, func() {
+		close(out)
+	})
+	return Stream[T]{p: p, c: out}
+}
+
+// SourceSeq adds a stage producing the items of seq
+func SourceSeq[T any](p *Pipeline, name string, buffer int, seq iter.Seq[T]) Stream[T] {
+	return Source(p, name, buffer, func(_ context.Context, emit func(T) bool) error {
+		for v := range seq {
+			if !emit(v) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// Stage adds a stage transforming the items of in with fn on workers goroutines, buffering up to buffer results.
+// The order of the items is not kept when there are multiple workers
+func Stage[In any, Out any](in Stream[In], name string, workers int, buffer int, fn func(context.Context, In) (Out, error)) Stream[Out] {
+	p := in.p
+	out := make(chan Out, buffer)
+	s := p.addStage(name, workers)
+
+	p.run(s, func() {
+		for v := range receive(p.ctx, in.c) {
+			var r Out
+			err := p.call(func() (err error) {
+				r, err = fn(p.ctx, v)
+				return err
+			})
+			if err != nil {
+				p.fail(s, err)
+				return
+			}
+
+			select {
+			case out <- r:
+				s.processed.Add(1)
+			case <-p.ctx.Done():
+				return
+			}
+		}
+	}, func() {
+		close(out)
+	})
+	return Stream[Out]{p: p, c: out}
+}
+
+// Sink adds a final stage consuming the items of in with fn on workers goroutines
+func Sink[T any](in Stream[T], name string, workers int, fn func(context.Context, T) error) {
+	p := in.p
+	s := p.addStage(name, workers)
+
+	p.run(s, func() {
+		for v := range receive(p.ctx, in.c) {
+			if err := p.call(func() error { return fn(p.ctx, v) }); err != nil {
+				p.fail(s, err)
+				return
+			}
+			s.processed.Add(1)
+		}
+	}, func() {})
+}
+
+// Wait waits for all the stages to finish, returning their errors as *StageError, along with the context's
+// error if it was canceled by the caller
+func (p *Pipeline) Wait() *ErrorSyncGroup {
+	p.wg.Wait()
+	p.cancel()
+	if err := p.parent.Err(); err != nil {
+		p.errs.Add(err)
+	}
+	return p.errs
+}
+
+// Stats returns the statistics of each stage, in the order they were added. It can be called while running
+func (p *Pipeline) Stats() []StageStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	stats := make([]StageStats, len(p.stages))
+	for i, s := range p.stages {
+		elapsed := time.Since(p.start)
+		if end := s.end.Load(); end != 0 {
+			elapsed = time.Unix(0, end).Sub(p.start)
+		}
+
+		stats[i] = StageStats{
+			Name:      s.name,
+			Workers:   s.workers,
+			Processed: s.processed.Load(),
+			Failed:    s.failed.Load(),
+			Elapsed:   elapsed,
+		}
+		if elapsed > 0 {
+			stats[i].Rate = float64(stats[i].Processed) / elapsed.Seconds()
+		}
+	}
+	return stats
+}
+
+func (p *Pipeline) addStage(name string, workers int) *stageStats {
+	s := &stageStats{name: name, workers: max(workers, 1)}
+	p.mutex.Lock()
+	p.stages = append(p.stages, s)
+	p.mutex.Unlock()
+	return s
+}
+
+// run starts the workers of a stage, calling done once they all returned
+func (p *Pipeline) run(s *stageStats, work func(), done func()) {
+	var stage sync.WaitGroup
+	stage.Add(s.workers)
+	p.wg.Add(s.workers + 1)
+	for range s.workers {
+		go func() {
+			defer p.wg.Done()
+			defer stage.Done()
+			work()
+		}()
+	}
+
+	go func() {
+		defer p.wg.Done()
+		stage.Wait()
+		s.end.Store(time.Now().UnixNano())
+		done()
+	}()
+}
+
+// call calls fn, recovering a panic into a *PanicError
+func (p *Pipeline) call(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Unit: -1, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// fail records the error of a stage and cancels the pipeline.
+// Errors caused by the cancellation of the pipeline are not recorded
+func (p *Pipeline) fail(s *stageStats, err error) {
+	if ctxErr := p.ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+		return
+	}
+	s.failed.Add(1)
+	p.errs.Add(&StageError{Stage: s.name, Err: err})
+	p.cancel()
+}
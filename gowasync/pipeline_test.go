@@ -0,0 +1,109 @@
+package gowasync
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeline(t *testing.T) {
+	p := NewPipeline(context.Background())
+
+	read := SourceSeq(p, "read", 10, givenItems(1000))
+	transform := Stage(read, "transform", 4, 10, func(_ context.Context, v int) (string, error) {
+		return strconv.Itoa(v * 2), nil
+	})
+
+	var mutex sync.Mutex
+	written := map[string]bool{}
+	Sink(transform, "write", 2, func(_ context.Context, v string) error {
+		mutex.Lock()
+		written[v] = true
+		mutex.Unlock()
+		return nil
+	})
+
+	assert.Nil(t, p.Wait().Err())
+	assert.Len(t, written, 1000)
+	assert.True(t, written["1998"])
+
+	stats := p.Stats()
+	assert.Len(t, stats, 3)
+	for i, name := range []string{"read", "transform", "write"} {
+		assert.Equal(t, name, stats[i].Name)
+		assert.Equal(t, int64(1000), stats[i].Processed)
+		assert.Equal(t, int64(0), stats[i].Failed)
+		assert.Greater(t, stats[i].Rate, 0.0)
+	}
+	assert.Equal(t, 4, stats[1].Workers)
+}
+
+func TestPipelineErrors(t *testing.T) {
+	t.Run("an error cancels every stage", func(t *testing.T) {
+		p := NewPipeline(context.Background())
+
+		var produced int64
+		read := Source(p, "read", 1, func(ctx context.Context, emit func(int) bool) error {
+			for i := 0; ; i++ {
+				if !emit(i) {
+					return ctx.Err()
+				}
+				atomic.AddInt64(&produced, 1)
+			}
+		})
+		transform := Stage(read, "transform", 2, 1, func(_ context.Context, v int) (int, error) {
+			if v == 50 {
+				return 0, errors.New("bad row")
+			}
+			return v, nil
+		})
+		Sink(transform, "write", 1, func(context.Context, int) error {
+			return nil
+		})
+
+		errs := p.Wait()
+		assert.Equal(t, 1, errs.Len())
+		assert.EqualError(t, errs.GetFirst(), "gowasync: stage transform: bad row")
+		assert.Less(t, produced, int64(100))
+		assert.Equal(t, int64(1), p.Stats()[1].Failed)
+	})
+
+	t.Run("panics are recovered", func(t *testing.T) {
+		p := NewPipeline(context.Background())
+		Sink(SourceSeq(p, "read", 0, givenItems(10)), "write", 1, func(_ context.Context, v int) error {
+			if v == 5 {
+				panic("boom")
+			}
+			return nil
+		})
+
+		var panicErr *PanicError
+		assert.ErrorAs(t, p.Wait().Err(), &panicErr)
+	})
+
+	t.Run("canceling shuts down cleanly", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		p := NewPipeline(ctx)
+		read := Source(p, "read", 0, func(_ context.Context, emit func(int) bool) error {
+			for emit(1) {
+			}
+			return nil
+		})
+		Sink(read, "write", 1, func(ctx context.Context, _ int) error {
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+
+		errs := p.Wait()
+		assert.Equal(t, 1, errs.Len())
+		assert.ErrorIs(t, errs, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,171 @@
+package gowasync
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrPoolFull   = errors.New("gowasync: pool queue is full")
+	ErrPoolClosed = errors.New("gowasync: pool is closed")
+)
+
+// RejectPolicy is what a Pool does with a task submitted while its queue is full
+type RejectPolicy int
+
+const (
+	RejectAbort      RejectPolicy = iota // Submit returns ErrPoolFull
+	RejectBlock                          // Submit waits for room in the queue
+	RejectCallerRuns                     // the task runs in the goroutine calling Submit, slowing down the producer
+)
+
+// Pool is a long-lived set of workers running submitted tasks, to avoid spawning goroutines for each work group.
+// Like goroutines, tasks must recover their own panics
+type Pool struct {
+	workers int
+	policy  RejectPolicy
+	tasks   chan func()
+	handoff chan func() // unbuffered, a send only succeeds when a worker is idle
+	wg      sync.WaitGroup
+
+	mutex  sync.RWMutex // held for writing to close tasks
+	closed bool
+
+	active    atomic.Int64
+	submitted atomic.Int64
+	completed atomic.Int64
+	rejected  atomic.Int64
+}
+
+// PoolStats is a snapshot of the activity of a Pool
+type PoolStats struct {
+	Workers   int
+	Active    int // workers running a task
+	Queued    int // tasks waiting for a worker
+	Submitted int64
+	Completed int64
+	Rejected  int64
+}
+
+// NewPool starts a pool of workers, queuing up to queueSize tasks while they are all busy
+func NewPool(workers int, queueSize int, policy RejectPolicy) *Pool {
+	p := &Pool{
+		workers: max(workers, 1),
+		policy:  policy,
+		tasks:   make(chan func(), max(queueSize, 0)),
+		handoff: make(chan func()),
+	}
+	p.wg.Add(p.workers)
+	for range p.workers {
+		go p.work()
+	}
+	return p
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case task, ok := <-p.tasks:
+			if !ok {
+				return
+			}
+			p.run(task)
+		case task := <-p.handoff:
+			p.run(task)
+		}
+	}
+}
+
+func (p *Pool) run(task func()) {
+	p.active.Add(1)
+	task()
+	p.active.Add(-1)
+	p.completed.Add(1)
+}
+
+// Submit queues task to be run by a worker. When the queue is full, the pool's RejectPolicy applies
+func (p *Pool) Submit(task func()) error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	p.submitted.Add(1)
+	select {
+	case p.tasks <- task:
+		return nil
+	default:
+	}
+
+	switch p.policy {
+	case RejectBlock:
+		p.tasks <- task
+	case RejectCallerRuns:
+		task()
+		p.completed.Add(1)
+	default:
+		p.submitted.Add(-1)
+		p.rejected.Add(1)
+		return ErrPoolFull
+	}
+	return nil
+}
+
+// handOff gives task to an idle worker, whatever the policy. It never queues task, so that it can't wait behind
+// the tasks keeping the workers busy
+func (p *Pool) handOff(task func()) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.handoff <- task:
+		p.submitted.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops accepting tasks, and waits for the queued ones to be run
+func (p *Pool) Close() {
+	p.mutex.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mutex.Unlock()
+	p.wg.Wait()
+}
+
+// Stats returns a snapshot of the activity of the pool
+func (p *Pool) Stats() PoolStats {
+	return PoolStats{
+		Workers:   p.workers,
+		Active:    int(p.active.Load()),
+		Queued:    len(p.tasks),
+		Submitted: p.submitted.Load(),
+		Completed: p.completed.Load(),
+		Rejected:  p.rejected.Load(),
+	}
+}
+
+// SetPool makes the workers of the group run on p rather than on goroutines spawned for each execution.
+// Workers for which p has no idle worker run on their own goroutine, so that executions never wait on other groups
+// nor deadlock when started from a task of p
+func (t *Group[T]) SetPool(p *Pool) {
+	t.pool = p
+}
+
+// spawn runs worker on an idle worker of the pool of the group, or on a new goroutine
+func (t *Group[T]) spawn(worker func()) {
+	if t.pool != nil && t.pool.handOff(worker) {
+		return
+	}
+	go worker()
+}
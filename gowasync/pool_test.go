@@ -0,0 +1,184 @@
+package gowasync
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// givenBusyPool returns a pool whose workers are all blocked until release is closed
+func givenBusyPool(workers int, queueSize int, policy RejectPolicy) (*Pool, chan struct{}) {
+	p := NewPool(workers, queueSize, policy)
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	started.Add(workers)
+	for range workers {
+		// retried until a worker is idle, without counting rejections
+		for !p.handOff(func() {
+			started.Done()
+			<-release
+		}) {
+			runtime.Gosched()
+		}
+	}
+	started.Wait()
+	return p, release
+}
+
+func TestPool(t *testing.T) {
+	p := NewPool(4, 10, RejectBlock)
+
+	var sum int64
+	for i := 1; i <= 100; i++ {
+		assert.NoError(t, p.Submit(func() {
+			atomic.AddInt64(&sum, int64(i))
+		}))
+	}
+	p.Close()
+
+	assert.Equal(t, int64(5050), sum)
+	assert.Equal(t, PoolStats{Workers: 4, Submitted: 100, Completed: 100}, p.Stats())
+	assert.ErrorIs(t, p.Submit(func() {}), ErrPoolClosed)
+}
+
+func TestPoolReject(t *testing.T) {
+	t.Run("abort", func(t *testing.T) {
+		p, release := givenBusyPool(2, 1, RejectAbort)
+		assert.NoError(t, p.Submit(func() {}))
+		assert.ErrorIs(t, p.Submit(func() {}), ErrPoolFull)
+
+		stats := p.Stats()
+		assert.Equal(t, 2, stats.Active)
+		assert.Equal(t, 1, stats.Queued)
+		assert.Equal(t, int64(3), stats.Submitted)
+		assert.Equal(t, int64(1), stats.Rejected)
+
+		close(release)
+		p.Close()
+		assert.Equal(t, int64(3), p.Stats().Completed)
+	})
+
+	t.Run("caller runs", func(t *testing.T) {
+		p, release := givenBusyPool(1, 0, RejectCallerRuns)
+		ran := false
+		assert.NoError(t, p.Submit(func() {
+			ran = true
+		}))
+		assert.True(t, ran)
+
+		close(release)
+		p.Close()
+	})
+
+	t.Run("block", func(t *testing.T) {
+		p, release := givenBusyPool(1, 0, RejectBlock)
+		submitted := make(chan error)
+		go func() {
+			submitted <- p.Submit(func() {})
+		}()
+
+		select {
+		case <-submitted:
+			t.Fatal("submit should wait for room")
+		case <-time.After(10 * time.Millisecond):
+		}
+		close(release)
+		assert.NoError(t, <-submitted)
+		p.Close()
+	})
+}
+
+func TestWorkGroupPool(t *testing.T) {
+	p := NewPool(4, 4, RejectAbort)
+	defer p.Close()
+
+	for run := 0; run < 10; run++ {
+		var sum int64
+		wg := NewWorkGroup(4, givenChunks(20, 5), func(data []int) error {
+			for _, v := range data {
+				atomic.AddInt64(&sum, int64(v))
+			}
+			return nil
+		})
+		wg.SetPool(p)
+
+		assert.Nil(t, wg.AwaitExecute().Err())
+		assert.Equal(t, int64(99*100/2), sum)
+	}
+
+	t.Run("workers without room in the pool run anyway", func(t *testing.T) {
+		busy, release := givenBusyPool(1, 0, RejectAbort)
+		defer func() {
+			close(release)
+			busy.Close()
+		}()
+
+		wg := NewWorkGroup(2, givenChunks(10, 1), func(data []int) error {
+			return nil
+		})
+		wg.SetPool(busy)
+		assert.Nil(t, wg.AwaitExecute().Err())
+		assert.Equal(t, int64(1), busy.Stats().Submitted)
+	})
+	t.Run("groups started from a task of the pool don't deadlock", func(t *testing.T) {
+		p := NewPool(1, 10, RejectAbort)
+		done := make(chan error, 1)
+		assert.NoError(t, p.Submit(func() {
+			wg := NewWorkGroup(2, givenChunks(10, 1), func(data []int) error {
+				return nil
+			})
+			wg.SetPool(p)
+			done <- wg.AwaitExecute().Err()
+		}))
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("nested execution deadlocked")
+		}
+		p.Close()
+	})
+
+	t.Run("groups don't wait on each other", func(t *testing.T) {
+		p := NewPool(2, 10, RejectAbort)
+		defer p.Close()
+
+		release := make(chan struct{})
+		var started sync.WaitGroup
+		started.Add(2)
+		slow := NewWorkGroup(2, givenChunks(2, 1), func(data []int) error {
+			started.Done()
+			<-release
+			return nil
+		})
+		slow.SetPool(p)
+		slowDone := make(chan error, 1)
+		go func() {
+			slowDone <- slow.AwaitExecute().Err()
+		}()
+		started.Wait()
+
+		fast := NewWorkGroup(2, givenChunks(10, 1), func(data []int) error {
+			return nil
+		})
+		fast.SetPool(p)
+		fastDone := make(chan error, 1)
+		go func() {
+			fastDone <- fast.AwaitExecute().Err()
+		}()
+
+		select {
+		case err := <-fastDone:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Error("group waited for the slow one")
+		}
+		close(release)
+		assert.NoError(t, <-slowDone)
+	})
+}
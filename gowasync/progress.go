@@ -0,0 +1,211 @@
+package gowasync
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Progress is a snapshot of the execution of a work group, given to a ProgressReporter
+type Progress struct {
+	Worker    int           // worker of a unit event, -1 otherwise
+	Unit      int           // index of the work unit of a unit event, -1 otherwise
+	UnitItems int           // items of the work unit of a unit event
+	Done      int           // work units processed successfully
+	Failed    int           // work units which failed
+	Items     int           // items of the processed work units, failed ones included
+	Total     int           // work units to process, -1 when unknown such as for streams
+	Elapsed   time.Duration // time since the start of the execution
+	Rate      float64       // processed items per second
+	ETA       time.Duration // estimated remaining time, -1 when unknown
+}
+
+// ProgressReporter is notified of the progress of a work group execution.
+// Calls are serialized, so implementations don't need locking but should return quickly
+type ProgressReporter interface {
+	OnStart(p Progress)
+	OnUnitDone(p Progress)
+	OnUnitFailed(p Progress, err error)
+	OnFinish(p Progress)
+}
+
+// progressTracker counts the processed units and notifies the reporters
+type progressTracker struct {
+	mutex     sync.Mutex
+	reporters []ProgressReporter
+	start     time.Time
+	progress  Progress
+}
+
+func newProgressTracker(total int, reporters []ProgressReporter) *progressTracker {
+	pt := &progressTracker{
+		reporters: reporters,
+		start:     time.Now(),
+		progress:  Progress{Worker: -1, Unit: -1, Total: total, ETA: -1},
+	}
+	for _, r := range reporters {
+		r.OnStart(pt.progress)
+	}
+	return pt
+}
+
+func (pt *progressTracker) unitDone(worker int, unit int, items int, err error) {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if err != nil {
+		pt.progress.Failed++
+	} else {
+		pt.progress.Done++
+	}
+	pt.progress.Items += items
+	p := pt.snapshot()
+	p.Worker, p.Unit, p.UnitItems = worker, unit, items
+
+	for _, r := range pt.reporters {
+		if err != nil {
+			r.OnUnitFailed(p, err)
+		} else {
+			r.OnUnitDone(p)
+		}
+	}
+}
+
+func (pt *progressTracker) finish() {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	p := pt.snapshot()
+	p.ETA = 0
+	for _, r := range pt.reporters {
+		r.OnFinish(p)
+	}
+}
+
+// snapshot returns the progress with its timings updated, pt.mutex must be held
+func (pt *progressTracker) snapshot() Progress {
+	p := pt.progress
+	p.Elapsed = time.Since(pt.start)
+	if s := p.Elapsed.Seconds(); s > 0 {
+		p.Rate = float64(p.Items) / s
+	}
+
+	processed := p.Done + p.Failed
+	if p.Total >= 0 && processed > 0 {
+		p.ETA = p.Elapsed / time.Duration(processed) * time.Duration(max(p.Total-processed, 0))
+	}
+	return p
+}
+
+// NewPrintReporter returns a reporter writing a line per event to w, as done in verbose mode
+func NewPrintReporter(w io.Writer) ProgressReporter {
+	return printReporter{w: w}
+}
+
+type printReporter struct {
+	w io.Writer
+}
+
+func (r printReporter) OnStart(Progress) {}
+
+func (r printReporter) OnUnitDone(p Progress) {
+	fmt.Fprintf(r.w, "worker %d consumed %d objects. Group speed at %.2f/s\n", p.Worker, p.Items, p.Rate)
+}
+
+func (r printReporter) OnUnitFailed(p Progress, err error) {
+	fmt.Fprintf(r.w, "worker %d failed work unit %d: %v\n", p.Worker, p.Unit, err)
+}
+
+func (r printReporter) OnFinish(p Progress) {
+	fmt.Fprintf(r.w, "Work group done processing %d messages in %s\n", p.Items, p.Elapsed)
+}
+
+// NewSlogReporter returns a reporter logging the start and finish of executions and failed units to logger,
+// and processed units at the debug level
+func NewSlogReporter(logger *slog.Logger) ProgressReporter {
+	return slogReporter{logger: logger}
+}
+
+type slogReporter struct {
+	logger *slog.Logger
+}
+
+func (r slogReporter) OnStart(p Progress) {
+	r.logger.Info("work group started", "total", p.Total)
+}
+
+func (r slogReporter) OnUnitDone(p Progress) {
+	r.logger.Debug("work unit done",
+		"worker", p.Worker, "unit", p.Unit, "items", p.UnitItems,
+		"done", p.Done, "failed", p.Failed, "total", p.Total, "rate", p.Rate, "eta", p.ETA)
+}
+
+func (r slogReporter) OnUnitFailed(p Progress, err error) {
+	r.logger.Warn("work unit failed",
+		"worker", p.Worker, "unit", p.Unit, "items", p.UnitItems, "error", err,
+		"done", p.Done, "failed", p.Failed, "total", p.Total)
+}
+
+func (r slogReporter) OnFinish(p Progress) {
+	r.logger.Info("work group finished",
+		"done", p.Done, "failed", p.Failed, "items", p.Items, "elapsed", p.Elapsed, "rate", p.Rate)
+}
+
+// Counter is a monotonic metric, such as a Prometheus counter
+type Counter interface {
+	Add(v float64)
+}
+
+// Gauge is a metric which can go up and down, such as a Prometheus gauge
+type Gauge interface {
+	Set(v float64)
+}
+
+// MetricsReporter exports the progress of executions to metrics. nil metrics are ignored
+type MetricsReporter struct {
+	UnitsDone   Counter
+	UnitsFailed Counter
+	Items       Counter
+	Rate        Gauge // items per second of the current execution
+	ETA         Gauge // estimated remaining seconds of the current execution
+}
+
+func (r *MetricsReporter) OnStart(p Progress) {
+	r.setGauges(p)
+}
+
+func (r *MetricsReporter) OnUnitDone(p Progress) {
+	if r.UnitsDone != nil {
+		r.UnitsDone.Add(1)
+	}
+	r.addItems(p)
+}
+
+func (r *MetricsReporter) OnUnitFailed(p Progress, _ error) {
+	if r.UnitsFailed != nil {
+		r.UnitsFailed.Add(1)
+	}
+	r.addItems(p)
+}
+
+func (r *MetricsReporter) OnFinish(p Progress) {
+	r.setGauges(p)
+}
+
+func (r *MetricsReporter) addItems(p Progress) {
+	if r.Items != nil {
+		r.Items.Add(float64(p.UnitItems))
+	}
+	r.setGauges(p)
+}
+
+func (r *MetricsReporter) setGauges(p Progress) {
+	if r.Rate != nil {
+		r.Rate.Set(p.Rate)
+	}
+	if r.ETA != nil && p.ETA >= 0 {
+		r.ETA.Set(p.ETA.Seconds())
+	}
+}
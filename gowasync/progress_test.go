@@ -0,0 +1,132 @@
+package gowasync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingReporter struct {
+	started  []Progress
+	done     []Progress
+	failed   []Progress
+	finished []Progress
+}
+
+func (r *recordingReporter) OnStart(p Progress)               { r.started = append(r.started, p) }
+func (r *recordingReporter) OnUnitDone(p Progress)            { r.done = append(r.done, p) }
+func (r *recordingReporter) OnUnitFailed(p Progress, _ error) { r.failed = append(r.failed, p) }
+func (r *recordingReporter) OnFinish(p Progress)              { r.finished = append(r.finished, p) }
+
+type testMetric struct {
+	value float64
+}
+
+func (m *testMetric) Add(v float64) { m.value += v }
+func (m *testMetric) Set(v float64) { m.value = v }
+
+func givenFailingOddGroup() *Group[int] {
+	return NewWorkGroup(3, givenChunks(10, 2), func(data []int) error {
+		time.Sleep(time.Millisecond)
+		if data[0]%4 == 2 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+}
+
+func TestProgressReporter(t *testing.T) {
+	r := &recordingReporter{}
+	wg := givenFailingOddGroup()
+	wg.SetProgressReporter(r)
+	wg.AwaitExecute()
+
+	assert.Len(t, r.started, 1)
+	assert.Equal(t, 10, r.started[0].Total)
+	assert.Equal(t, time.Duration(-1), r.started[0].ETA)
+
+	assert.Len(t, r.done, 5)
+	assert.Len(t, r.failed, 5)
+	for i, p := range append(r.done, r.failed...) {
+		assert.Equal(t, 2, p.UnitItems, i)
+		assert.GreaterOrEqual(t, p.Worker, 0)
+		assert.GreaterOrEqual(t, p.ETA, time.Duration(0))
+	}
+
+	assert.Len(t, r.finished, 1)
+	last := r.finished[0]
+	assert.Equal(t, 5, last.Done)
+	assert.Equal(t, 5, last.Failed)
+	assert.Equal(t, 20, last.Items)
+	assert.Equal(t, time.Duration(0), last.ETA)
+	assert.Greater(t, last.Rate, 0.0)
+	assert.GreaterOrEqual(t, last.Elapsed, 3*time.Millisecond)
+
+	t.Run("streams have no total", func(t *testing.T) {
+		r := &recordingReporter{}
+		wg := NewStreamWorkGroup(2, 3, givenItems(10), func(_ context.Context, data []int) error {
+			return nil
+		})
+		wg.SetProgressReporter(r)
+		wg.AwaitExecute()
+
+		assert.Equal(t, -1, r.finished[0].Total)
+		assert.Equal(t, 4, r.finished[0].Done)
+		for _, p := range r.done {
+			assert.Equal(t, time.Duration(-1), p.ETA)
+		}
+	})
+}
+
+func TestPrintReporter(t *testing.T) {
+	var out bytes.Buffer
+	wg := givenFailingOddGroup()
+	wg.SetProgressReporter(NewPrintReporter(&out))
+	wg.AwaitExecute()
+
+	lines := strings.Split(out.String(), "\n")
+	assert.Len(t, lines, 12) // one per unit and the final one, all ending with a newline
+	assert.Equal(t, "", lines[11])
+	assert.True(t, strings.HasPrefix(lines[10], "Work group done processing 20 messages in "))
+}
+
+func TestSlogReporter(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	wg := givenFailingOddGroup()
+	wg.SetProgressReporter(NewSlogReporter(logger))
+	wg.AwaitExecute()
+
+	log := out.String()
+	assert.Equal(t, 1, strings.Count(log, `msg="work group started" total=10`))
+	assert.Equal(t, 5, strings.Count(log, `level=DEBUG msg="work unit done"`))
+	assert.Equal(t, 5, strings.Count(log, `level=WARN msg="work unit failed"`))
+	assert.Equal(t, 1, strings.Count(log, `msg="work group finished" done=5 failed=5 items=20`))
+}
+
+func TestMetricsReporter(t *testing.T) {
+	done, failed, items, rate, eta := &testMetric{}, &testMetric{}, &testMetric{}, &testMetric{}, &testMetric{value: -1}
+	wg := givenFailingOddGroup()
+	wg.SetProgressReporter(&MetricsReporter{UnitsDone: done, UnitsFailed: failed, Items: items, Rate: rate, ETA: eta})
+	wg.AwaitExecute()
+
+	assert.Equal(t, 5.0, done.value)
+	assert.Equal(t, 5.0, failed.value)
+	assert.Equal(t, 20.0, items.value)
+	assert.Greater(t, rate.value, 0.0)
+	assert.Equal(t, 0.0, eta.value)
+
+	t.Run("nil metrics are ignored", func(t *testing.T) {
+		wg := givenFailingOddGroup()
+		wg.SetProgressReporter(&MetricsReporter{UnitsDone: done})
+		wg.AwaitExecute()
+		assert.Equal(t, 10.0, done.value)
+	})
+}
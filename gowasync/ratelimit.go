@@ -0,0 +1,112 @@
+package gowasync
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateUnit is what a RateLimit counts
+type RateUnit int
+
+const (
+	RateItems     RateUnit = iota // items of the chunks, such as rows
+	RateWorkUnits                 // work units, whatever their size
+)
+
+// RateLimit caps the throughput of a work group with a token bucket
+type RateLimit struct {
+	PerSecond float64  // maximum sustained throughput, none when 0
+	Burst     int      // amount which can be consumed at once after being idle. PerSecond rounded up when 0
+	Unit      RateUnit // what is counted
+}
+
+// tokenBucket is a rate limiter allowing to consume more tokens than it holds, the debt being waited for
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait takes n tokens, waiting until they are available. It returns false if ctx is canceled first
+func (b *tokenBucket) wait(ctx context.Context, n int) bool {
+	b.mutex.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= float64(n)
+	missing := -b.tokens
+	b.mutex.Unlock()
+
+	if missing <= 0 {
+		return true
+	}
+	return sleep(ctx, time.Duration(missing/b.rate*float64(time.Second)))
+}
+
+// concurrencyCap limits the number of units processed at once, to a limit which can change at any time
+type concurrencyCap struct {
+	mutex   sync.Mutex
+	limit   int // none when 0
+	active  int
+	changed chan struct{} // closed when a slot is released or the limit changes
+}
+
+func newConcurrencyCap() *concurrencyCap {
+	return &concurrencyCap{changed: make(chan struct{})}
+}
+
+// acquire waits for a free slot. It returns false if ctx is canceled first
+func (c *concurrencyCap) acquire(ctx context.Context) bool {
+	for {
+		c.mutex.Lock()
+		if c.limit <= 0 || c.active < c.limit {
+			c.active++
+			c.mutex.Unlock()
+			return true
+		}
+		changed := c.changed
+		c.mutex.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (c *concurrencyCap) release() {
+	c.mutex.Lock()
+	c.active--
+	c.notify()
+	c.mutex.Unlock()
+}
+
+func (c *concurrencyCap) set(limit int) {
+	c.mutex.Lock()
+	c.limit = limit
+	c.notify()
+	c.mutex.Unlock()
+}
+
+// notify wakes up the waiting acquirers, c.mutex must be held
+func (c *concurrencyCap) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
@@ -0,0 +1,103 @@
+package gowasync
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(100, 10)
+	ctx := context.Background()
+
+	start := time.Now()
+	assert.True(t, b.wait(ctx, 10)) // the burst is available right away
+	assert.Less(t, time.Since(start), 5*time.Millisecond)
+
+	assert.True(t, b.wait(ctx, 5)) // 5 tokens at 100/s
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	t.Run("canceling stops waiting", func(t *testing.T) {
+		b := newTokenBucket(1, 1)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.True(t, b.wait(ctx, 1))
+		assert.False(t, b.wait(ctx, 1))
+	})
+}
+
+func TestWorkGroupRateLimit(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		wg := NewWorkGroup(4, givenChunks(10, 10), func(data []int) error {
+			return nil
+		})
+		wg.SetRateLimit(RateLimit{PerSecond: 1000, Burst: 20})
+
+		// 100 items with a burst of 20 at 1000/s
+		start := time.Now()
+		assert.Nil(t, wg.AwaitExecute().GetFirst())
+		assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+	})
+
+	t.Run("work units", func(t *testing.T) {
+		wg := NewWorkGroup(4, givenChunks(10, 10), func(data []int) error {
+			return nil
+		})
+		wg.SetRateLimit(RateLimit{PerSecond: 100, Burst: 1, Unit: RateWorkUnits})
+
+		start := time.Now()
+		assert.Nil(t, wg.AwaitExecute().GetFirst())
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	})
+}
+
+func TestWorkGroupConcurrency(t *testing.T) {
+	var active, maxActive, processed int64
+	var wg *Group[int]
+	wg = NewWorkGroup(8, givenChunks(40, 1), func(data []int) error {
+		cur := atomic.AddInt64(&active, 1)
+		defer atomic.AddInt64(&active, -1)
+		for {
+			m := atomic.LoadInt64(&maxActive)
+			if cur <= m || atomic.CompareAndSwapInt64(&maxActive, m, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		if atomic.AddInt64(&processed, 1) == 20 {
+			assert.LessOrEqual(t, atomic.LoadInt64(&maxActive), int64(2))
+			wg.SetConcurrency(0) // lifted while running
+		}
+		return nil
+	})
+	wg.SetConcurrency(2)
+
+	assert.Nil(t, wg.AwaitExecute().GetFirst())
+	assert.Equal(t, int64(40), processed)
+	assert.LessOrEqual(t, maxActive, int64(8))
+}
+
+func TestWorkGroupRateLimitRetries(t *testing.T) {
+	var attempts int64
+	var failed sync.Map
+	wg := NewWorkGroup(2, givenChunks(2, 10), func(data []int) error {
+		atomic.AddInt64(&attempts, 1)
+		if _, retried := failed.LoadOrStore(data[0], true); !retried {
+			return errTransient
+		}
+		return nil
+	})
+	wg.SetRetryPolicy(RetryPolicy{MaxAttempts: 2})
+	wg.SetRateLimit(RateLimit{PerSecond: 1000, Burst: 20})
+
+	// 2 units of 10 items attempted twice: 40 items with a burst of 20 at 1000/s
+	start := time.Now()
+	assert.Nil(t, wg.AwaitExecute().Err())
+	assert.Equal(t, int64(4), attempts)
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+}
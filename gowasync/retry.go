@@ -0,0 +1,90 @@
+package gowasync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy defines how failed work units are retried
+type RetryPolicy struct {
+	MaxAttempts int                  // attempts per unit, including the first one. 0 or 1 disables retries
+	BaseDelay   time.Duration        // delay before the first retry, doubled after each attempt
+	MaxDelay    time.Duration        // upper bound of the delay, none when 0
+	Jitter      float64              // fraction of the delay randomly removed, between 0 and 1, so workers retrying together spread out
+	Retryable   func(err error) bool // whether err is transient and worth retrying. nil retries every error but panics. Timeouts are never retried
+}
+
+// RetryError is the error of a work unit still failing after being attempted more than once
+type RetryError struct {
+	Attempts int
+	Err      error // error of the last attempt
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("gowasync: failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// delay returns the time to wait before the given retry, starting at 1
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(float64(d) * min(p.Jitter, 1) * rand.Float64())
+	}
+	return d
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	// a timed out attempt may still be running, retrying would process its chunk twice at once
+	if errors.Is(err, ErrUnitTimeout) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	// a panic is most likely deterministic, so it would happen again
+	var panicErr *PanicError
+	return !errors.As(err, &panicErr)
+}
+
+// retry calls attempt until it succeeds, the policy gives up or ctx is canceled while waiting.
+// The error is wrapped in a *RetryError when attempt was called more than once
+func (p RetryPolicy) retry(ctx context.Context, attempt func() error) error {
+	for attempts := 1; ; attempts++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		if attempts >= p.MaxAttempts || !p.retryable(err) || !sleep(ctx, p.delay(attempts)) {
+			if attempts > 1 {
+				return &RetryError{Attempts: attempts, Err: err}
+			}
+			return err
+		}
+	}
+}
+
+// sleep waits for d, returning false when ctx is canceled first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,113 @@
+package gowasync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTransient = errors.New("transient")
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, p.delay(1))
+	assert.Equal(t, 20*time.Millisecond, p.delay(2))
+	assert.Equal(t, 40*time.Millisecond, p.delay(3))
+	assert.Equal(t, 50*time.Millisecond, p.delay(4))
+	assert.Equal(t, 50*time.Millisecond, p.delay(100))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.delay(2)
+		assert.GreaterOrEqual(t, d, 10*time.Millisecond)
+		assert.LessOrEqual(t, d, 20*time.Millisecond)
+	}
+}
+
+func TestWorkGroupRetry(t *testing.T) {
+	t.Run("transient failures are retried", func(t *testing.T) {
+		var mutex sync.Mutex
+		attempts := map[int]int{}
+
+		wg := NewWorkGroup(2, givenChunks(10, 1), func(data []int) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			attempts[data[0]]++
+			if attempts[data[0]] < 3 {
+				return errTransient
+			}
+			return nil
+		})
+		wg.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+		assert.Nil(t, wg.AwaitExecute().GetFirst())
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, 3, attempts[i])
+		}
+	})
+
+	t.Run("attempts are reported when giving up", func(t *testing.T) {
+		wg := NewWorkGroup(1, givenChunks(1, 1), func(data []int) error {
+			return errTransient
+		})
+		wg.SetRetryPolicy(RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond})
+
+		err := wg.AwaitExecute().GetFirst()
+		var retryErr *RetryError
+		assert.ErrorAs(t, err, &retryErr)
+		assert.Equal(t, 4, retryErr.Attempts)
+		assert.ErrorIs(t, err, errTransient)
+	})
+
+	t.Run("permanent errors are not retried", func(t *testing.T) {
+		errPermanent := errors.New("permanent")
+		attempts := 0
+		wg := NewWorkGroup(1, givenChunks(1, 1), func(data []int) error {
+			attempts++
+			return errPermanent
+		})
+		wg.SetRetryPolicy(RetryPolicy{
+			MaxAttempts: 4,
+			Retryable: func(err error) bool {
+				return errors.Is(err, errTransient)
+			},
+		})
+
+		assert.ErrorIs(t, wg.AwaitExecute().GetFirst(), errPermanent)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("panics are not retried by default", func(t *testing.T) {
+		attempts := 0
+		wg := NewWorkGroup(1, givenChunks(1, 1), func(data []int) error {
+			attempts++
+			panic("bad record")
+		})
+		wg.SetRetryPolicy(RetryPolicy{MaxAttempts: 4})
+
+		var panicErr *PanicError
+		assert.ErrorAs(t, wg.AwaitExecute().GetFirst(), &panicErr)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("canceling stops waiting between attempts", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		wg := NewWorkGroup(1, givenChunks(1, 1), func(data []int) error {
+			return errTransient
+		})
+		wg.SetRetryPolicy(RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour})
+
+		start := time.Now()
+		errs := wg.AwaitExecuteContext(ctx)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.ErrorIs(t, errs.GetFirst(), errTransient)
+		assert.False(t, errors.As(errs.GetFirst(), new(*RetryError))) // attempted once
+		assert.ErrorIs(t, errs.errs[1], context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,68 @@
+package gowasync
+
+import (
+	"context"
+	"iter"
+	"sort"
+)
+
+// Schedule is the order in which work units are dispatched to workers
+type Schedule int
+
+const (
+	ScheduleInOrder      Schedule = iota // in the order of the chunks
+	ScheduleLargestFirst                 // heaviest first, by weight (see SetWeights) or else by number of items
+)
+
+// SetSchedule sets the order in which work units are dispatched. Dispatching the largest units first
+// avoids a few large units scheduled last stretching the execution while the other workers are idle.
+// Streaming work groups are always dispatched in order
+func (t *Group[T]) SetSchedule(s Schedule) {
+	t.schedule = s
+}
+
+// SetWeights sets the weight, or priority, of each work unit in the order of the chunks, used by ScheduleLargestFirst
+func (t *Group[T]) SetWeights(weights []float64) {
+	if t.total >= 0 && len(weights) != t.total {
+		panic("gowasync: there must be one weight per work unit")
+	}
+	t.weights = weights
+}
+
+// units returns the work units in their dispatch order
+func (t *Group[T]) units(ctx context.Context) iter.Seq[workUnit[T]] {
+	inOrder := func(yield func(workUnit[T]) bool) {
+		index := 0
+		for data := range t.source(ctx) {
+			if !yield(workUnit[T]{index: index, data: data}) {
+				return
+			}
+			index++
+		}
+	}
+	if t.schedule == ScheduleInOrder || t.total < 0 {
+		return inOrder
+	}
+
+	return func(yield func(workUnit[T]) bool) {
+		units := make([]workUnit[T], 0, t.total)
+		for wu := range inOrder {
+			units = append(units, wu)
+		}
+		sort.SliceStable(units, func(i, j int) bool {
+			return t.weight(units[i]) > t.weight(units[j])
+		})
+		for _, wu := range units {
+			if !yield(wu) {
+				return
+			}
+		}
+	}
+}
+
+func (t *Group[T]) weight(wu workUnit[T]) float64 {
+	if t.weights != nil {
+		return t.weights[wu.index]
+	}
+	return float64(len(wu.data))
+}
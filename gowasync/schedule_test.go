@@ -0,0 +1,79 @@
+package gowasync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func givenDispatchOrder(wg *Group[int]) *[]int {
+	var order []int
+	wg.onDispatch = func(index int) {
+		order = append(order, index)
+	}
+	return &order
+}
+
+func TestWorkGroupSchedule(t *testing.T) {
+	chunks := [][]int{{1}, {1, 2, 3}, {}, {1, 2, 3, 4, 5}, {1, 2}, {1, 2, 3}}
+	process := func(data []int) error {
+		return nil
+	}
+
+	t.Run("in order by default", func(t *testing.T) {
+		wg := NewWorkGroup(2, chunks, process)
+		order := givenDispatchOrder(wg)
+		assert.Nil(t, wg.AwaitExecute().Err())
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, *order)
+	})
+
+	t.Run("largest first", func(t *testing.T) {
+		wg := NewWorkGroup(2, chunks, process)
+		wg.SetSchedule(ScheduleLargestFirst)
+		order := givenDispatchOrder(wg)
+		assert.Nil(t, wg.AwaitExecute().Err())
+		assert.Equal(t, []int{3, 1, 5, 4, 0, 2}, *order) // ties keep their order
+	})
+
+	t.Run("by weight", func(t *testing.T) {
+		var processed []int
+		wg := NewWorkGroup(1, chunks, func(data []int) error {
+			processed = append(processed, len(data))
+			return nil
+		})
+		wg.SetSchedule(ScheduleLargestFirst)
+		wg.SetWeights([]float64{0, 1, 10, 2, 0.5, 3})
+		order := givenDispatchOrder(wg)
+
+		assert.Nil(t, wg.AwaitExecute().Err())
+		assert.Equal(t, []int{2, 5, 3, 1, 4, 0}, *order)
+		assert.Equal(t, []int{0, 3, 5, 3, 2, 1}, processed)
+	})
+
+	t.Run("units keep their index", func(t *testing.T) {
+		mg := NewMapGroup(2, chunks, func(data []int) ([]int, error) {
+			return []int{len(data)}, nil
+		})
+		mg.SetSchedule(ScheduleLargestFirst)
+		res := mg.AwaitExecute()
+		assert.Equal(t, []int{1, 3, 0, 5, 2, 3}, res.Flatten())
+	})
+
+	t.Run("streams are dispatched in order", func(t *testing.T) {
+		wg := NewStreamWorkGroup(2, 2, givenItems(7), func(context.Context, []int) error {
+			return nil
+		})
+		wg.SetSchedule(ScheduleLargestFirst)
+		order := givenDispatchOrder(wg)
+		assert.Nil(t, wg.AwaitExecute().Err())
+		assert.Equal(t, []int{0, 1, 2, 3}, *order)
+	})
+
+	t.Run("weights must match the work units", func(t *testing.T) {
+		wg := NewWorkGroup(2, chunks, process)
+		assert.Panics(t, func() {
+			wg.SetWeights([]float64{1, 2})
+		})
+	})
+}
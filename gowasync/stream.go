@@ -0,0 +1,49 @@
+package gowasync
+
+import (
+	"context"
+	"iter"
+
+	"gowtools/gowslice"
+)
+
+// NewStreamWorkGroup returns a work group processing data in chunks of batchSize items, read from data as workers are ready.
+// Unlike NewWorkGroup, data is never fully held in memory, so it suits sources larger than memory such as database exports.
+// data is iterated once per execution
+func NewStreamWorkGroup[T any](workers uint32, batchSize uint, data iter.Seq[T], process func(context.Context, []T) error) *Group[T] {
+	source := func(context.Context) iter.Seq[[]T] {
+		return gowslice.ChunkSeq(data, batchSize)
+	}
+	return newGroup(workers, -1, source, func(ctx context.Context, _ int, data []T) (func(), error) {
+		return nil, process(ctx, data)
+	})
+}
+
+// NewChanWorkGroup returns a work group processing the items received on data in chunks of batchSize items,
+// until data is closed or the execution canceled. The producer is blocked while all workers are busy
+func NewChanWorkGroup[T any](workers uint32, batchSize uint, data <-chan T, process func(context.Context, []T) error) *Group[T] {
+	source := func(ctx context.Context) iter.Seq[[]T] {
+		return gowslice.ChunkSeq(receive(ctx, data), batchSize)
+	}
+	g := newGroup(workers, -1, source, func(ctx context.Context, _ int, data []T) (func(), error) {
+		return nil, process(ctx, data)
+	})
+	g.sourceCancelable = true
+	return g
+}
+
+// receive returns the items received on c until it is closed or ctx is canceled
+func receive[T any](ctx context.Context, c <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case v, ok := <-c:
+				if !ok || !yield(v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package gowasync
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func givenItems(n int) func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestStreamWorkGroup(t *testing.T) {
+	var sum, batches int64
+	wg := NewStreamWorkGroup(4, 10, givenItems(1005), func(_ context.Context, data []int) error {
+		assert.LessOrEqual(t, len(data), 10)
+		atomic.AddInt64(&batches, 1)
+		for _, v := range data {
+			atomic.AddInt64(&sum, int64(v))
+		}
+		return nil
+	})
+
+	assert.Nil(t, wg.AwaitExecute().GetFirst())
+	assert.Equal(t, int64(1004*1005/2), sum)
+	assert.Equal(t, int64(101), batches)
+
+	t.Run("reading is bounded by the workers", func(t *testing.T) {
+		var read, processed int64
+		source := func(yield func(int) bool) {
+			for i := 0; i < 1000; i++ {
+				// at most one batch per worker can wait in the buffer, plus one being processed by each worker and one being read
+				assert.LessOrEqual(t, atomic.LoadInt64(&read)-atomic.LoadInt64(&processed), int64(5*10))
+				atomic.AddInt64(&read, 1)
+				if !yield(i) {
+					return
+				}
+			}
+		}
+		wg := NewStreamWorkGroup(2, 10, source, func(_ context.Context, data []int) error {
+			atomic.AddInt64(&processed, int64(len(data)))
+			return nil
+		})
+		assert.Nil(t, wg.AwaitExecute().GetFirst())
+		assert.Equal(t, int64(1000), processed)
+	})
+}
+
+func TestChanWorkGroup(t *testing.T) {
+	data := make(chan int)
+	go func() {
+		for i := 0; i < 95; i++ {
+			data <- i
+		}
+		close(data)
+	}()
+
+	var sum int64
+	wg := NewChanWorkGroup(3, 10, data, func(_ context.Context, data []int) error {
+		for _, v := range data {
+			atomic.AddInt64(&sum, int64(v))
+		}
+		return nil
+	})
+	assert.Nil(t, wg.AwaitExecute().GetFirst())
+	assert.Equal(t, int64(94*95/2), sum)
+
+	t.Run("canceling stops waiting on the producer", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		data := make(chan int)
+		go func() {
+			data <- 1
+			cancel() // never closes data
+		}()
+
+		wg := NewChanWorkGroup(1, 10, data, func(context.Context, []int) error {
+			return nil
+		})
+		assert.ErrorIs(t, wg.AwaitExecuteContext(ctx).GetFirst(), context.Canceled)
+	})
+}
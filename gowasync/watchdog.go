@@ -0,0 +1,115 @@
+package gowasync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrUnitTimeout is the error of a work unit which exceeded the timeout of its work group
+var ErrUnitTimeout = errors.New("gowasync: work unit timed out")
+
+// StuckUnit is a work unit running for longer than the threshold of a watchdog
+type StuckUnit struct {
+	Unit    int // index of the work unit's chunk
+	Worker  int
+	Running time.Duration
+}
+
+// inFlight tracks the work units being processed
+type inFlight struct {
+	mutex sync.Mutex
+	units map[int]*runningUnit
+}
+
+type runningUnit struct {
+	worker   int
+	start    time.Time
+	reported time.Time // last time reported as stuck
+}
+
+func newInFlight() *inFlight {
+	return &inFlight{units: make(map[int]*runningUnit)}
+}
+
+func (f *inFlight) begin(unit int, worker int) {
+	f.mutex.Lock()
+	f.units[unit] = &runningUnit{worker: worker, start: time.Now()}
+	f.mutex.Unlock()
+}
+
+func (f *inFlight) end(unit int) {
+	f.mutex.Lock()
+	delete(f.units, unit)
+	f.mutex.Unlock()
+}
+
+func (f *inFlight) len() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.units)
+}
+
+// stuck returns the units running past threshold which were not reported during the last threshold
+func (f *inFlight) stuck(threshold time.Duration) []StuckUnit {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := time.Now()
+	var stuck []StuckUnit
+	for unit, r := range f.units {
+		if now.Sub(r.start) >= threshold && now.Sub(r.reported) >= threshold {
+			r.reported = now
+			stuck = append(stuck, StuckUnit{Unit: unit, Worker: r.worker, Running: now.Sub(r.start)})
+		}
+	}
+	return stuck
+}
+
+// watch calls report every threshold for each unit running past it, until ctx is done
+func (f *inFlight) watch(ctx context.Context, threshold time.Duration, report func(StuckUnit)) {
+	ticker := time.NewTicker(max(threshold/4, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, s := range f.stuck(threshold) {
+				report(s)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runWithTimeout calls run with a context canceled after timeout, returning an error wrapping ErrUnitTimeout when it expires.
+// When run doesn't return before its context is done, it keeps going in the background, its result is discarded and
+// the returned abandoned channel is closed once it returns. run must not panic
+func runWithTimeout(parent context.Context, timeout time.Duration, run func(context.Context) (func(), error)) (commit func(), err error, abandoned <-chan struct{}) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+
+	type result struct {
+		commit func()
+		err    error
+	}
+	results := make(chan result, 1)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		defer cancel()
+		commit, err := run(ctx)
+		results <- result{commit, err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.commit, r.err, nil
+	case <-ctx.Done():
+		if err := parent.Err(); err != nil {
+			return nil, err, finished
+		}
+		return nil, fmt.Errorf("%w after %s", ErrUnitTimeout, timeout), finished
+	}
+}
@@ -0,0 +1,131 @@
+package gowasync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkGroupUnitTimeout(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	wg := NewWorkGroup(2, givenChunks(4, 1), func(data []int) error {
+		if data[0] == 2 {
+			<-hang // ignores the context
+		}
+		return nil
+	})
+	wg.SetUnitTimeout(20 * time.Millisecond)
+
+	start := time.Now()
+	errs := wg.AwaitExecute()
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, errs.Len())
+	assert.ErrorIs(t, errs.GetFirst(), ErrUnitTimeout)
+
+	var unitErr *UnitError
+	assert.ErrorAs(t, errs.GetFirst(), &unitErr)
+	assert.Equal(t, 2, unitErr.Unit)
+
+	t.Run("the unit's context is canceled", func(t *testing.T) {
+		canceled := make(chan error, 1)
+		wg := NewWorkGroupContext(1, givenChunks(1, 1), func(ctx context.Context, data []int) error {
+			<-ctx.Done()
+			canceled <- ctx.Err()
+			return ctx.Err()
+		})
+		wg.SetUnitTimeout(5 * time.Millisecond)
+
+		errs := wg.AwaitExecute()
+		assert.Equal(t, 1, errs.Len())
+		assert.ErrorIs(t, <-canceled, context.DeadlineExceeded)
+	})
+
+	t.Run("the deadline of the caller is not a unit timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		wg := NewWorkGroup(1, givenChunks(1, 1), func(data []int) error {
+			<-hang
+			return nil
+		})
+		wg.SetUnitTimeout(time.Hour)
+
+		errs := wg.AwaitExecuteContext(ctx)
+		assert.ErrorIs(t, errs, context.DeadlineExceeded)
+		assert.False(t, errors.Is(errs, ErrUnitTimeout))
+	})
+
+	t.Run("timed out units are not retried", func(t *testing.T) {
+		var attempts atomic.Int64
+		wg := NewWorkGroup(1, givenChunks(1, 1), func(data []int) error {
+			attempts.Add(1)
+			<-hang
+			return nil
+		})
+		wg.SetUnitTimeout(5 * time.Millisecond)
+		wg.SetRetryPolicy(RetryPolicy{MaxAttempts: 3})
+
+		assert.ErrorIs(t, wg.AwaitExecute().GetFirst(), ErrUnitTimeout)
+		assert.Equal(t, int64(1), attempts.Load())
+	})
+
+	t.Run("timed out units keep their concurrency slot until they return", func(t *testing.T) {
+		slow := make(chan struct{})
+		var released atomic.Bool
+		wg := NewWorkGroup(2, givenChunks(2, 1), func(data []int) error {
+			if data[0] == 0 {
+				<-slow
+				released.Store(true)
+				return nil
+			}
+			if !released.Load() {
+				return errors.New("started while the timed out unit was running")
+			}
+			return nil
+		})
+		wg.SetUnitTimeout(5 * time.Millisecond)
+		wg.SetConcurrency(1)
+		time.AfterFunc(50*time.Millisecond, func() { close(slow) })
+
+		errs := wg.AwaitExecute()
+		assert.Equal(t, 1, errs.Len())
+		assert.ErrorIs(t, errs.GetFirst(), ErrUnitTimeout)
+	})
+}
+
+func TestWorkGroupWatchdog(t *testing.T) {
+	var mutex sync.Mutex
+	var stuck []StuckUnit
+
+	wg := NewWorkGroup(2, givenChunks(10, 1), func(data []int) error {
+		if data[0] == 7 {
+			time.Sleep(60 * time.Millisecond)
+		}
+		return nil
+	})
+	wg.SetWatchdog(20*time.Millisecond, func(s StuckUnit) {
+		mutex.Lock()
+		stuck = append(stuck, s)
+		mutex.Unlock()
+	})
+
+	assert.Nil(t, wg.AwaitExecute().Err())
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	// reported about every threshold while running
+	assert.GreaterOrEqual(t, len(stuck), 1)
+	assert.LessOrEqual(t, len(stuck), 3)
+	for _, s := range stuck {
+		assert.Equal(t, 7, s.Unit)
+		assert.GreaterOrEqual(t, s.Running, 20*time.Millisecond)
+		assert.Contains(t, []int{0, 1}, s.Worker)
+	}
+}
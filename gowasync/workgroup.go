@@ -1,7 +1,12 @@
 package gowasync
 
 import (
+	"context"
 	"fmt"
+	"iter"
+	"os"
+	"runtime/debug"
+	"slices"
 	"sync/atomic"
 	"time"
 )
@@ -9,79 +14,329 @@ import (
 // WorkGroup is to run a given 'global' (to the work group) process on data
 // in parallel on an X amount of workers
 type WorkGroup interface {
-	AwaitExecute() ErrorSyncGroup
+	AwaitExecute() *ErrorSyncGroup
+	AwaitExecuteContext(ctx context.Context) *ErrorSyncGroup
 	SetVerbose(v bool)
 }
 
-type defaultWorkGroup[T any] struct {
-	workers   uint32
-	workUnits []workUnit[T]
-	verbose   bool
+// Group is the WorkGroup returned by the constructors. Its settings are methods of the struct rather than of the
+// interface, so that adding one doesn't break the other implementations of WorkGroup
+type Group[T any] struct {
+	workers     uint32
+	total       int                                     // number of work units, -1 when unknown
+	source      func(ctx context.Context) iter.Seq[[]T] // chunks of data to process, read as workers are ready
+	process     processFunc[T]
+	verbose     bool
+	reporter    ProgressReporter
+	failFast    bool
+	retry       RetryPolicy
+	errorLimit  int
+	unitTimeout time.Duration
+	watchdog    time.Duration // threshold of the watchdog, disabled when 0
+	onStuck     func(StuckUnit)
+	schedule    Schedule
+	weights     []float64
+	checkpoint  CheckpointStore
+	executions  executions
+	pool        *Pool
+	limit       RateLimit
+	concurrency *concurrencyCap
+
+	sourceCancelable bool // source can end when its context is canceled, before its last chunk
+
+	onUnitDone     func(index int, err error) // called by workers after each processed unit
+	onDispatch     func(index int)            // called before dispatching each unit, for tests
+	onCheckpointed func(index int)            // called for each unit skipped because the checkpoint holds it
 }
 
+// processFunc processes the chunk at index. The returned commit function, which can be nil, applies the result of
+// the attempt. It is called by the worker only when the result is kept, so that a timed out attempt never applies it
+type processFunc[T any] func(ctx context.Context, index int, data []T) (commit func(), err error)
+
 type workUnit[T any] struct {
-	data    []T
-	process func([]T) error
+	index int // position of the chunk in the original data
+	data  []T
 }
 
 // NewWorkGroup returns a work group that can be executed to process data asynchronously
+func NewWorkGroup[T any](workers uint32, dataChunked [][]T, process func([]T) error) *Group[T] {
+	return NewWorkGroupContext(workers, dataChunked, func(_ context.Context, data []T) error {
+		return process(data)
+	})
+}
 
-func NewWorkGroup[T any](workers uint32, dataChunked [][]T, process func([]T) error) WorkGroup {
-	units := prepareWorkUnits[T](dataChunked, process)
-	return &defaultWorkGroup[T]{
-		workers:   workers,
-		workUnits: units,
-		verbose:   false,
+// NewWorkGroupContext returns a work group like NewWorkGroup, whose process receives the execution context.
+// The context is canceled when the parent one is, or on the first error in fail fast mode, so process can abort its I/O
+func NewWorkGroupContext[T any](workers uint32, dataChunked [][]T, process func(context.Context, []T) error) *Group[T] {
+	return newGroup(workers, len(dataChunked), sliceSource(dataChunked), func(ctx context.Context, _ int, data []T) (func(), error) {
+		return nil, process(ctx, data)
+	})
+}
+
+func newGroup[T any](workers uint32, total int, source func(context.Context) iter.Seq[[]T], process processFunc[T]) *Group[T] {
+	return &Group[T]{
+		workers:     workers,
+		total:       total,
+		source:      source,
+		process:     process,
+		verbose:     false,
+		concurrency: newConcurrencyCap(),
 	}
 }
 
-func prepareWorkUnits[T any](dataChunked [][]T, p func([]T) error) []workUnit[T] {
-	wu := make([]workUnit[T], len(dataChunked))
-	for i := range dataChunked {
-		wu[i] = workUnit[T]{
-			data:    dataChunked[i],
-			process: p,
-		}
+func sliceSource[T any](dataChunked [][]T) func(context.Context) iter.Seq[[]T] {
+	return func(context.Context) iter.Seq[[]T] {
+		return slices.Values(dataChunked)
 	}
-	return wu
 }
 
-func (t *defaultWorkGroup[T]) SetVerbose(v bool) {
+// SetVerbose prints the progress of the executions to the standard output (see NewPrintReporter)
+func (t *Group[T]) SetVerbose(v bool) {
 	t.verbose = v
 }
 
-func (t *defaultWorkGroup[T]) AwaitExecute() ErrorSyncGroup {
-	var count int64
-	start := time.Now()
+// SetProgressReporter makes r notified of the progress of the executions, nil to remove it
+func (t *Group[T]) SetProgressReporter(r ProgressReporter) {
+	t.reporter = r
+}
+
+// SetFailFast makes the execution stop on the first error: remaining work units are not processed
+// and the context given to process is canceled
+func (t *Group[T]) SetFailFast(v bool) {
+	t.failFast = v
+}
+
+// SetRetryPolicy makes failed work units be processed again according to p.
+// Units succeeding after retries are not reported as errors
+func (t *Group[T]) SetRetryPolicy(p RetryPolicy) {
+	t.retry = p
+}
+
+// SetRateLimit caps the throughput of the group, work units waiting for the limit before being processed
+func (t *Group[T]) SetRateLimit(l RateLimit) {
+	t.limit = l
+}
+
+// SetConcurrency caps the number of work units processed at once under the number of workers, none when n is 0.
+// Unlike the other settings, it can be changed while the group is executing
+func (t *Group[T]) SetConcurrency(n int) {
+	t.concurrency.set(n)
+}
+
+// SetErrorLimit caps the number of errors stored by the executions (see ErrorSyncGroup.SetLimit)
+func (t *Group[T]) SetErrorLimit(n int) {
+	t.errorLimit = n
+}
+
+// SetUnitTimeout cancels the context of each attempt at processing a work unit after d, recording an error wrapping
+// ErrUnitTimeout. A process ignoring its context is left running in the background, so that the execution still ends:
+// its result is discarded, it is not retried and it keeps its slot of the concurrency cap until it returns
+func (t *Group[T]) SetUnitTimeout(d time.Duration) {
+	t.unitTimeout = d
+}
+
+// SetWatchdog calls report every threshold for each work unit running past threshold, to detect stuck workers
+func (t *Group[T]) SetWatchdog(threshold time.Duration, report func(StuckUnit)) {
+	t.watchdog = threshold
+	t.onStuck = report
+}
+
+// SetCheckpoint records the work units completed successfully to store, and skips the units it already holds
+func (t *Group[T]) SetCheckpoint(store CheckpointStore) {
+	t.checkpoint = store
+}
+
+func (t *Group[T]) AwaitExecute() *ErrorSyncGroup {
+	return t.AwaitExecuteContext(context.Background())
+}
+
+// newProgressTracker returns a tracker of total units notifying the reporters of the group, nil if there are none
+func (t *Group[T]) newProgressTracker(total int) *progressTracker {
+	var reporters []ProgressReporter
+	if t.reporter != nil {
+		reporters = append(reporters, t.reporter)
+	}
+	if t.verbose {
+		reporters = append(reporters, NewPrintReporter(os.Stdout))
+	}
+	if len(reporters) == 0 {
+		return nil
+	}
+	return newProgressTracker(total, reporters)
+}
+
+// limitCost returns the tokens of the rate limit consumed by wu
+func (t *Group[T]) limitCost(wu workUnit[T]) int {
+	if t.limit.Unit == RateWorkUnits {
+		return 1
+	}
+	return len(wu.data)
+}
+
+// runUnit processes a work unit, recovering a panic of process into a *PanicError
+func (t *Group[T]) runUnit(ctx context.Context, wu workUnit[T]) (commit func(), err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			commit, err = nil, &PanicError{Unit: wu.index, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return t.process(ctx, wu.index, wu.data)
+}
+
+// AwaitExecuteContext processes all the work units, unless ctx is canceled first in which case
+// the units not started yet are skipped and ctx's error is added to the returned errors.
+// The errors of the units are wrapped in a *UnitError
+func (t *Group[T]) AwaitExecuteContext(parent context.Context) *ErrorSyncGroup {
+	completed := map[int]bool{}
+	if t.checkpoint != nil {
+		indexes, err := t.checkpoint.Load()
+		if err != nil {
+			errs := &ErrorSyncGroup{}
+			errs.Add(fmt.Errorf("gowasync: loading checkpoint: %w", err))
+			return errs
+		}
+		for _, i := range indexes {
+			completed[i] = true
+		}
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
 
-	workChan := make(chan workUnit[T], 1000)
+	// canceled by Stop, new units are not started while the ones in flight keep ctx
+	dispatchCtx, stopDispatch := context.WithCancel(ctx)
+	defer stopDispatch()
+
+	total := t.total
+	if total >= 0 {
+		total -= len(completed)
+	}
+	exec := newExecution(cancel, stopDispatch, total)
+	t.executions.start(exec)
+	defer close(exec.finished)
+
+	// a buffer of one unit per worker keeps them busy, while blocking the reading of the source until they are ready
+	workChan := make(chan workUnit[T], t.workers)
 	done := make(chan bool, t.workers) // channel to signal completion
-	errs := ErrorSyncGroup{}
+	errs := &ErrorSyncGroup{limit: t.errorLimit}
+
+	// set when units are left unprocessed by a cancellation or a stop, so that a stop after the last unit is not an error
+	var skipped atomic.Bool
+
+	var limiter *tokenBucket
+	if t.limit.PerSecond > 0 {
+		limiter = newTokenBucket(t.limit.PerSecond, t.limit.Burst)
+	}
+
+	progress := t.newProgressTracker(total)
+
+	running := newInFlight()
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	watchDone := make(chan bool)
+	go func() {
+		if t.watchdog > 0 && t.onStuck != nil {
+			running.watch(watchCtx, t.watchdog, t.onStuck)
+		}
+		close(watchDone)
+	}()
 
 	// Spawn worker goroutines
 	for i := 0; uint32(i) < t.workers; i++ {
-		go func(i int) {
+		t.spawn(func() {
 			// each worker waits for a new chunk then processes it
 			for wu := range workChan {
-				if err := wu.process(wu.data); err != nil {
-					errs.Add(err)
+				if dispatchCtx.Err() != nil || !t.concurrency.acquire(dispatchCtx) {
+					// canceled or stopped, drain the remaining units without processing them
+					skipped.Store(true)
+					continue
+				}
+				if limiter != nil && !limiter.wait(dispatchCtx, t.limitCost(wu)) {
+					t.concurrency.release()
+					skipped.Store(true)
+					continue
 				}
 
-				if t.verbose {
-					cur := atomic.AddInt64(&count, int64(len(wu.data)))
-					rps := float64(cur) / time.Since(start).Seconds()
-					fmt.Printf("worker %d consumed %d objects. Group speed at speed %.2f/s \n", i, cur, rps)
+				exec.started.Add(1)
+				running.begin(wu.index, i)
+				var commit func()
+				var abandoned <-chan struct{}
+				attempt := 0
+				err := t.retry.retry(ctx, func() (err error) {
+					// every attempt consumes the rate limit, the first one having waited before starting
+					attempt++
+					if attempt > 1 && limiter != nil && !limiter.wait(ctx, t.limitCost(wu)) {
+						return ctx.Err()
+					}
+					if t.unitTimeout > 0 {
+						// a timed out attempt may still be running, its result is discarded and it is not retried
+						commit, err, abandoned = runWithTimeout(ctx, t.unitTimeout, func(ctx context.Context) (func(), error) {
+							return t.runUnit(ctx, wu)
+						})
+						return err
+					}
+					commit, err = t.runUnit(ctx, wu)
+					return err
+				})
+				running.end(wu.index)
+				if abandoned != nil {
+					// the slot is held until the abandoned attempt returns, so that the concurrency cap still holds
+					go func() {
+						<-abandoned
+						t.concurrency.release()
+					}()
+				} else {
+					t.concurrency.release()
+				}
+				if err == nil && commit != nil {
+					commit()
+				}
+				if err == nil && t.checkpoint != nil {
+					if cpErr := t.checkpoint.Mark(wu.index); cpErr != nil {
+						err = fmt.Errorf("gowasync: checkpoint: %w", cpErr)
+					}
+				}
+				if err != nil {
+					errs.Add(&UnitError{Unit: wu.index, Worker: i, Err: err})
+					if t.failFast {
+						cancel()
+					}
 				}
+				if t.onUnitDone != nil {
+					t.onUnitDone(wu.index, err)
+				}
+				if progress != nil {
+					progress.unitDone(i, wu.index, len(wu.data), err)
+				}
+				exec.done.Add(1)
 			}
 
 			// Signal completion to the main function when the worker goroutine finishes
 			done <- true
-		}(i)
+		})
 	}
 
-	// Dispatch each work unit to workers
-	for _, unitOfWork := range t.workUnits {
-		workChan <- unitOfWork
+	// Dispatch each work unit to workers, until canceled or stopped
+dispatch:
+	for wu := range t.units(dispatchCtx) {
+		if completed[wu.index] {
+			if t.onCheckpointed != nil {
+				t.onCheckpointed(wu.index)
+			}
+			continue
+		}
+		if t.onDispatch != nil {
+			t.onDispatch(wu.index)
+		}
+		select {
+		case workChan <- wu:
+		case <-dispatchCtx.Done():
+			skipped.Store(true)
+			break dispatch
+		}
+	}
+	if t.sourceCancelable && dispatchCtx.Err() != nil {
+		// the source ended on the cancellation, it may have had chunks left
+		skipped.Store(true)
 	}
 
 	close(workChan) // close() causes for range to exit once all has been processed in the channel
@@ -91,8 +346,19 @@ func (t *defaultWorkGroup[T]) AwaitExecute() ErrorSyncGroup {
 		<-done
 	}
 
-	if t.verbose {
-		fmt.Printf("Work group done processing %d messages in %s", count, time.Since(start).String())
+	// no unit is reported as stuck once the execution returned
+	stopWatch()
+	<-watchDone
+
+	if err := parent.Err(); err != nil {
+		errs.Add(err)
+	}
+	if exec.stopped.Load() && skipped.Load() {
+		errs.Add(ErrStopped)
+	}
+
+	if progress != nil {
+		progress.finish()
 	}
 
 	return errs
@@ -0,0 +1,148 @@
+package gowasync
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func givenChunks(n int, size int) [][]int {
+	chunks := make([][]int, n)
+	for i := range chunks {
+		chunks[i] = make([]int, size)
+		for j := range chunks[i] {
+			chunks[i][j] = i*size + j
+		}
+	}
+	return chunks
+}
+
+func TestWorkGroup(t *testing.T) {
+	var sum int64
+	wg := NewWorkGroup(4, givenChunks(50, 10), func(data []int) error {
+		for _, v := range data {
+			atomic.AddInt64(&sum, int64(v))
+		}
+		return nil
+	})
+
+	errs := wg.AwaitExecute()
+	assert.Nil(t, errs.GetFirst())
+	assert.Equal(t, int64(499*500/2), sum)
+
+	t.Run("errors are collected", func(t *testing.T) {
+		wg := NewWorkGroup(4, givenChunks(10, 1), func(data []int) error {
+			if data[0]%2 == 0 {
+				return errors.New("even")
+			}
+			return nil
+		})
+		errs := wg.AwaitExecute()
+		assert.Equal(t, 5, errs.Len())
+		for _, err := range errs.Errors() {
+			var unitErr *UnitError
+			assert.ErrorAs(t, err, &unitErr)
+			assert.Equal(t, 0, unitErr.Unit%2)
+			assert.Less(t, unitErr.Worker, 4)
+			assert.EqualError(t, unitErr.Err, "even")
+		}
+	})
+}
+
+func TestWorkGroupContext(t *testing.T) {
+	t.Run("process receives the context", func(t *testing.T) {
+		type ctxKey struct{}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+		var seen int64
+		wg := NewWorkGroupContext(2, givenChunks(5, 1), func(ctx context.Context, data []int) error {
+			if ctx.Value(ctxKey{}) == "value" {
+				atomic.AddInt64(&seen, 1)
+			}
+			return nil
+		})
+		assert.Nil(t, wg.AwaitExecuteContext(ctx).GetFirst())
+		assert.Equal(t, int64(5), seen)
+	})
+
+	t.Run("canceling stops the dispatch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var processed int64
+		wg := NewWorkGroupContext(2, givenChunks(100, 1), func(ctx context.Context, data []int) error {
+			if atomic.AddInt64(&processed, 1) == 10 {
+				cancel()
+			}
+			return nil
+		})
+		errs := wg.AwaitExecuteContext(ctx)
+		assert.ErrorIs(t, errs.GetFirst(), context.Canceled)
+		assert.Less(t, processed, int64(100))
+	})
+
+	t.Run("fail fast cancels the remaining units", func(t *testing.T) {
+		var processed int64
+		wg := NewWorkGroupContext(2, givenChunks(100, 1), func(ctx context.Context, data []int) error {
+			atomic.AddInt64(&processed, 1)
+			if data[0] == 3 {
+				return errors.New("failed")
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Millisecond):
+			}
+			return nil
+		})
+		wg.SetFailFast(true)
+
+		errs := wg.AwaitExecute()
+		assert.ErrorContains(t, errs.Err(), "work unit 3 on worker")
+		assert.Equal(t, 1, errs.Len())
+		assert.Less(t, processed, int64(100))
+	})
+}
+
+func TestWorkGroupPanic(t *testing.T) {
+	var processed int64
+	wg := NewWorkGroup(2, givenChunks(10, 1), func(data []int) error {
+		if data[0] == 4 {
+			panic("bad record")
+		}
+		atomic.AddInt64(&processed, 1)
+		return nil
+	})
+
+	errs := wg.AwaitExecute()
+	assert.Equal(t, int64(9), processed)
+	assert.Len(t, errs.errs, 1)
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, errs.GetFirst(), &panicErr)
+	assert.Equal(t, 4, panicErr.Unit)
+	assert.Equal(t, "bad record", panicErr.Value)
+	assert.Contains(t, string(panicErr.Stack), "workgroup_test.go")
+	assert.EqualError(t, panicErr, "gowasync: work unit 4 panicked: bad record")
+
+	t.Run("panicked errors can be unwrapped", func(t *testing.T) {
+		errBad := errors.New("bad")
+		wg := NewWorkGroup(1, givenChunks(1, 1), func(data []int) error {
+			panic(errBad)
+		})
+		assert.ErrorIs(t, wg.AwaitExecute().GetFirst(), errBad)
+	})
+}
+
+func TestWorkGroupErrorLimit(t *testing.T) {
+	wg := NewWorkGroup(4, givenChunks(100, 1), func(data []int) error {
+		return errors.New("failed")
+	})
+	wg.SetErrorLimit(10)
+
+	errs := wg.AwaitExecute()
+	assert.Equal(t, 100, errs.Len())
+	assert.Len(t, errs.Errors(), 10)
+}
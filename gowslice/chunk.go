@@ -1,5 +1,7 @@
 package gowslice
 
+import "iter"
+
 // ChunkSlice breaks down a slice of data into multiple chunks
 // The function will always return nChunks, even if they are empty
 func ChunkSlice[T any](data []T, nChunks uint) [][]T {
@@ -28,3 +30,24 @@ func ChunkSlice[T any](data []T, nChunks uint) [][]T {
 
 	return chunks
 }
+
+// ChunkSeq breaks down a sequence of data into chunks of size items, the last one holding the remainder.
+// Chunks are read lazily from seq and never reused, so they can be kept by the caller.
+// A size of 0 returns all the data in a single chunk
+func ChunkSeq[T any](seq iter.Seq[T], size uint) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		var chunk []T
+		for v := range seq {
+			chunk = append(chunk, v)
+			if size > 0 && uint(len(chunk)) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = nil
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package gowslice
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"slices"
 	"testing"
 )
 
@@ -67,3 +68,73 @@ func TestChunkSlice(t *testing.T) {
 		assert.Equal(t, ut.expected, chunks, msg)
 	}
 }
+
+func TestChunkSeq(t *testing.T) {
+	var tests = []struct {
+		in       []int
+		size     uint
+		expected [][]int
+		testName string
+	}{
+		{
+			in:       nil,
+			size:     2,
+			expected: nil,
+			testName: "empty sequence",
+		},
+		{
+			in:   []int{1, 2, 3},
+			size: 0,
+			expected: [][]int{
+				{1, 2, 3},
+			},
+			testName: "size 0",
+		},
+		{
+			in:   []int{1, 2, 3, 4},
+			size: 2,
+			expected: [][]int{
+				{1, 2},
+				{3, 4},
+			},
+			testName: "with size dividing the input",
+		},
+		{
+			in:   []int{1, 2, 3, 4, 5, 6, 7},
+			size: 3,
+			expected: [][]int{
+				{1, 2, 3},
+				{4, 5, 6},
+				{7},
+			},
+			testName: "with size not perfectly dividing the input",
+		},
+	}
+
+	for _, ut := range tests {
+		var chunks [][]int
+		for chunk := range ChunkSeq(slices.Values(ut.in), ut.size) {
+			chunks = append(chunks, chunk)
+		}
+
+		msg := fmt.Sprintf("Test %s \nExpected %#v, got %#v", ut.testName, ut.expected, chunks)
+		assert.Equal(t, ut.expected, chunks, msg)
+	}
+
+	t.Run("stops reading when the consumer stops", func(t *testing.T) {
+		read := 0
+		seq := func(yield func(int) bool) {
+			for i := 0; i < 100; i++ {
+				read++
+				if !yield(i) {
+					return
+				}
+			}
+		}
+		for chunk := range ChunkSeq(seq, 10) {
+			assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, chunk)
+			break
+		}
+		assert.Equal(t, 10, read)
+	})
+}